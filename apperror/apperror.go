@@ -0,0 +1,157 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+)
+
+// Code tells API clients what went wrong without parsing the translated message.
+type Code string
+
+const (
+	CodeInvalidRequest   Code = "invalid_request"   // 400, a parameter or value is not acceptable
+	CodeValidationFailed Code = "validation_failed" // 400, the body failed validation, see fields
+	CodeUnauthorized     Code = "unauthorized"      // 401
+	CodeNotFound         Code = "not_found"         // 404
+	CodeConflict         Code = "conflict"          // 409, the resource is not in a state that allows this
+	CodeUnprocessable    Code = "unprocessable"     // 422, valid request the business rules refuse
+	CodeRateLimited      Code = "rate_limited"      // 429, see the Retry-After header
+	CodePaymentFailed    Code = "payment_failed"    // Midtrans refused or could not be reached
+	CodeInternal         Code = "internal_error"    // 500, the cause is logged, never returned
+)
+
+// Error is an error meant for API clients. Message is shown in the client's language,
+// Err is the underlying cause and is only logged.
+type Error struct {
+	Code    Code
+	Status  int
+	Message *i18n.Error
+	Fields  []Field // Set by InvalidField, Validation derives them from Err
+	Err     error
+}
+
+// Field is a request field that breaks a rule the binding tags cannot express.
+type Field struct {
+	Name    string // JSON path
+	Message *i18n.Error
+}
+
+// Sentinels for errors.Is, which matches any Error with the same code.
+var (
+	ErrInvalid       = &Error{Code: CodeInvalidRequest}
+	ErrUnauthorized  = &Error{Code: CodeUnauthorized}
+	ErrNotFound      = &Error{Code: CodeNotFound}
+	ErrConflict      = &Error{Code: CodeConflict}
+	ErrUnprocessable = &Error{Code: CodeUnprocessable}
+	ErrRateLimited   = &Error{Code: CodeRateLimited}
+	ErrPaymentFailed = &Error{Code: CodePaymentFailed}
+)
+
+// New returns an Error; format is the English catalog key of the message.
+func New(status int, code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Status: status, Message: &i18n.Error{Format: format, Args: args}}
+}
+
+func Invalid(format string, args ...interface{}) *Error {
+	return New(http.StatusBadRequest, CodeInvalidRequest, format, args...)
+}
+
+func Unauthorized(format string, args ...interface{}) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, format, args...)
+}
+
+func NotFound(format string, args ...interface{}) *Error {
+	return New(http.StatusNotFound, CodeNotFound, format, args...)
+}
+
+func Conflict(format string, args ...interface{}) *Error {
+	return New(http.StatusConflict, CodeConflict, format, args...)
+}
+
+func Unprocessable(format string, args ...interface{}) *Error {
+	return New(http.StatusUnprocessableEntity, CodeUnprocessable, format, args...)
+}
+
+// RateLimited tells a client it sent too many requests and may retry after seconds.
+func RateLimited(seconds int) *Error {
+	return New(http.StatusTooManyRequests, CodeRateLimited, "Too many requests, try again in %d seconds", seconds)
+}
+
+// Validation reports a ShouldBindJSON failure. The response lists every invalid field.
+func Validation(err error) *Error {
+	e := New(http.StatusBadRequest, CodeValidationFailed, "Invalid request data")
+	e.Err = err
+	return e
+}
+
+// InvalidField reports one invalid field like Validation does, for rules checked against
+// stored data, e.g. a category that does not exist.
+func InvalidField(name, format string, args ...interface{}) *Error {
+	e := New(http.StatusBadRequest, CodeValidationFailed, "Invalid request data")
+	e.Fields = []Field{{Name: name, Message: &i18n.Error{Format: format, Args: args}}}
+	return e
+}
+
+// Internal reports an unexpected failure. Only the message reaches the client.
+func Internal(err error, format string, args ...interface{}) *Error {
+	e := New(http.StatusInternalServerError, CodeInternal, format, args...)
+	e.Err = err
+	return e
+}
+
+// Payment reports a payment gateway failure. status is the HTTP status Midtrans answered
+// with, statuses outside 400-599 (including 0 when it could not be reached) become 502.
+func Payment(status int, err error) *Error {
+	if status < http.StatusBadRequest || status > 599 {
+		status = http.StatusBadGateway
+	}
+	e := New(status, CodePaymentFailed, "Internal Server Error: Payment")
+	e.Err = err
+	return e
+}
+
+// Wrap returns err unchanged when it already is an Error, otherwise it becomes an
+// internal error with the given message.
+func Wrap(err error, format string, args ...interface{}) error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return Internal(err, format, args...)
+}
+
+// From converts any error to an Error. An *i18n.Error carries a message written for the
+// client, so it becomes an invalid request, anything else an internal error.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var message *i18n.Error
+	if errors.As(err, &message) {
+		return &Error{Code: CodeInvalidRequest, Status: http.StatusBadRequest, Message: message}
+	}
+	return Internal(err, "Internal server error")
+}
+
+func (e *Error) Error() string {
+	message := string(e.Code)
+	if e.Message != nil {
+		message = e.Message.Error()
+	}
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
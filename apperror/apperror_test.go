@@ -0,0 +1,103 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func TestIs(t *testing.T) {
+	err := fmt.Errorf("checkout: %w", NotFound("Product with ID %d not found", 7))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("wrapped not found error does not match ErrNotFound")
+	}
+	if errors.Is(err, ErrConflict) {
+		t.Errorf("not found error matches ErrConflict")
+	}
+	if got := From(err); got.Status != http.StatusNotFound {
+		t.Errorf("From kept status %d, want 404", got.Status)
+	}
+}
+
+func serve(handler gin.HandlerFunc, body string) (*httptest.ResponseRecorder, Response) {
+	gin.SetMode(gin.TestMode)
+	i18n.UseJSONFieldNames()
+	r := gin.New()
+	r.Use(i18n.Middleware(), Middleware())
+	r.POST("/", handler)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Accept-Language", "id")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var response Response
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestMiddleware(t *testing.T) {
+	type request struct {
+		Email    string `json:"email" binding:"required,email"`
+		Quantity int    `json:"quantity" binding:"gt=0"`
+	}
+	bind := func(c *gin.Context) {
+		var r request
+		if err := c.ShouldBindJSON(&r); err != nil {
+			Abort(c, Validation(err))
+		}
+	}
+
+	w, response := serve(bind, `{"email": "budi", "quantity": 0}`)
+	if w.Code != http.StatusBadRequest || response.Error.Code != CodeValidationFailed {
+		t.Fatalf("got %d %+v, want 400 validation_failed", w.Code, response.Error)
+	}
+	if response.Error.Message != "Data permintaan tidak valid" {
+		t.Errorf("message not translated: %q", response.Error.Message)
+	}
+	if len(response.Error.Fields) != 2 || response.Error.Fields[0].Field != "email" || response.Error.Fields[1].Field != "quantity" {
+		t.Errorf("got fields %+v, want email and quantity", response.Error.Fields)
+	}
+
+	_, response = serve(bind, `{"email": `)
+	if response.Error.Code != CodeInvalidRequest || response.Error.Fields != nil {
+		t.Errorf("malformed body: got %+v, want invalid_request without fields", response.Error)
+	}
+
+	_, response = serve(func(c *gin.Context) {
+		Abort(c, InvalidField("category_id", "Category %d does not exist", 11))
+	}, "")
+	if response.Error.Code != CodeValidationFailed || len(response.Error.Fields) != 1 || response.Error.Fields[0].Message != "Kategori 11 tidak ada" {
+		t.Errorf("got %+v, want the translated category_id field", response.Error)
+	}
+
+	w, response = serve(func(c *gin.Context) {
+		Abort(c, errors.New(`pq: relation "orders" does not exist`))
+	}, "")
+	if w.Code != http.StatusInternalServerError || response.Error.Code != CodeInternal {
+		t.Fatalf("got %d %+v, want 500 internal_error", w.Code, response.Error)
+	}
+	if strings.Contains(w.Body.String(), "pq:") {
+		t.Errorf("database error leaked: %s", w.Body.String())
+	}
+}
+
+func TestMiddlewareRecoversPanics(t *testing.T) {
+	w, response := serve(func(c *gin.Context) {
+		var order *struct{ ID int }
+		_ = order.ID
+	}, "")
+	if w.Code != http.StatusInternalServerError || response.Error.Code != CodeInternal {
+		t.Fatalf("got %d %s, want the internal_error envelope", w.Code, w.Body.String())
+	}
+	if response.Error.Message != "Terjadi kesalahan server" {
+		t.Errorf("message not translated: %q", response.Error.Message)
+	}
+}
@@ -0,0 +1,87 @@
+package apperror
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// Response is the body of every error response.
+type Response struct {
+	Error Body `json:"error"`
+}
+
+type Body struct {
+	Code      Code              `json:"code"`
+	Message   string            `json:"message"`
+	Fields    []i18n.FieldError `json:"fields,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+// Abort stops the handler chain with err, Middleware renders it.
+func Abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
+
+// Middleware renders the last error a handler recorded with Abort or c.Error as a
+// Response, unless the handler already wrote a body. A panic is rendered as an
+// internal error.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// The client went away, there is nobody to answer
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			slog.ErrorContext(c.Request.Context(), "Handler panicked", "panic", recovered, "route", c.FullPath())
+			c.Abort()
+			if !c.Writer.Written() {
+				c.JSON(http.StatusInternalServerError, Render(c, Internal(nil, "Internal server error")))
+			}
+		}()
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := From(c.Errors.Last().Err)
+		if err.Status >= 500 {
+			slog.ErrorContext(c.Request.Context(), err.Message.Error(), "code", err.Code, "error", err.Err)
+		}
+		c.JSON(err.Status, Render(c, err))
+	}
+}
+
+// Render returns the response for err in the language of the request.
+func Render(c *gin.Context, err *Error) Response {
+	lang := i18n.FromContext(c)
+	body := Body{
+		Code:      err.Code,
+		Message:   i18n.T(lang, err.Message.Format, err.Message.Args...),
+		RequestID: logging.RequestID(c.Request.Context()),
+	}
+
+	for _, field := range err.Fields {
+		body.Fields = append(body.Fields, i18n.FieldError{
+			Field:   field.Name,
+			Message: i18n.T(lang, field.Message.Format, field.Message.Args...),
+		})
+	}
+
+	// A body that cannot be parsed has no fields to point at
+	if err.Code == CodeValidationFailed && err.Fields == nil {
+		if body.Fields = i18n.ValidationErrors(lang, err.Err); body.Fields == nil {
+			body.Code = CodeInvalidRequest
+			body.Message = i18n.T(lang, "Request body is not valid JSON")
+		}
+	}
+	return Response{Error: body}
+}
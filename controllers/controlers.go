@@ -1,279 +1,61 @@
 package controllers
 
 import (
-	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
-	"time"
 
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
 	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
 	"github.com/dimassfeb-09/pestapasta-be/models"
 	"github.com/dimassfeb-09/pestapasta-be/services"
 	"github.com/dimassfeb-09/pestapasta-be/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/jinzhu/gorm"
-	"golang.org/x/crypto/bcrypt"
 )
 
-func HandleLogin(c *gin.Context, db *gorm.DB) {
-	var loginRequest struct {
-		Username string `json:"username" binding:"required"`
-		Password string `json:"password" binding:"required"`
-	}
+func HandleLogin(c *gin.Context, users *services.UserService) {
+	var loginRequest models.LoginRequest
 
 	// Parse JSON input
 	if err := c.ShouldBindJSON(&loginRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
-		return
-	}
-
-	var user models.User
-	if err := db.Where("username = ?", loginRequest.Username).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Username or password"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
-		}
+		apperror.Abort(c, apperror.Validation(err))
 		return
 	}
 
-	// Verify the password (assuming it's hashed)
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginRequest.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Username or password"})
-		return
-	}
-
-	// Generate a JWT token (example token generation)
-	token, err := utils.GenerateJWT(uint(user.ID), user.Username)
+	token, err := users.Login(c.Request.Context(), loginRequest.Username, loginRequest.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		apperror.Abort(c, apperror.Wrap(err, "Error generating token"))
 		return
 	}
 
 	// Respond with the token
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
+		"message": tr(c, "Login successful"),
 		"token":   token,
 	})
 }
 
-func HandleCheckout(c *gin.Context, db *gorm.DB) {
+func HandleCheckout(c *gin.Context, orders *services.OrderService) {
 	var checkoutRequest models.CheckoutRequest
 
 	// Parse JSON input
 	if err := c.ShouldBindJSON(&checkoutRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		apperror.Abort(c, apperror.Validation(err))
 		return
 	}
+	checkoutRequest.Language = i18n.Resolve(checkoutRequest.Language, i18n.FromContext(c))
 
-	// Validate input
-	for _, product := range checkoutRequest.Products {
-		if product.Quantity <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity must be greater than 0"})
-			return
-		}
-	}
-
-	// Fetch products from DB
-	var products []models.Menu
-	productIDs := make([]int, len(checkoutRequest.Products))
-	for i, item := range checkoutRequest.Products {
-		productIDs[i] = item.ID
-	}
-
-	// Check if all products exist in the database
-	if err := db.Where("id IN (?)", productIDs).Find(&products).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching products"})
-		return
-	}
-
-	// Check if all requested products exist in the fetched products
-	productMap := make(map[int]models.Menu)
-	for _, product := range products {
-		productMap[product.ID] = product
-	}
-
-	for _, item := range checkoutRequest.Products {
-		if _, exists := productMap[item.ID]; !exists {
-			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Product with ID %d not found", item.ID)})
-			return
-		}
-	}
-
-	// Map quantities from request to products
-	productQuantities := make(map[int]int)
-	for _, item := range checkoutRequest.Products {
-		productQuantities[item.ID] = item.Quantity
-	}
-
-	// Calculate total price
-	total := 0.0
-	for _, product := range products {
-		quantity := productQuantities[product.ID]
-		total += product.Price * float64(quantity)
-	}
-
-	// Create order
-	order := models.Order{
-		OrderDate:   time.Now().Format("2006-01-02 15:04:05"),
-		TotalPrice:  total,
-		OrderStatus: "Pending",
-		Email:       checkoutRequest.Email,
-		Name:        checkoutRequest.Name,
-	}
-
-	// Fetch payment method
-	var paymentMethod models.PaymentMethod
-	if err := db.Where("id = ?", checkoutRequest.PaymentMethodID).First(&paymentMethod).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Payment method not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payment method"})
-		return
-	}
-
-	var midtransResponse *models.CreateTransactionMidtransResponse
-	if paymentMethod.Code == "qris" {
-		var itemDetails []models.ItemDetails
-		for _, item := range checkoutRequest.Products {
-			product, exists := productMap[item.ID]
-			if !exists {
-				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Product with ID %d not found", item.ID)})
-				return
-			}
-			itemDetails = append(itemDetails, models.ItemDetails{
-				ID:       fmt.Sprintf("PRODUCTID-%d", product.ID),
-				Price:    product.Price,
-				Quantity: item.Quantity,
-				Name:     product.Name,
-			})
-		}
-
-		midtransPayload := models.CreateTransactionMidtransPayload{
-			PaymentType: "qris",
-			ItemDetails: itemDetails,
-			TransactionDetails: struct {
-				OrderID     string  `json:"order_id"`
-				GrossAmount float64 `json:"gross_amount"`
-			}{
-				OrderID:     fmt.Sprintf("ORDER-%d", time.Now().Unix()), // Generate dynamic OrderID
-				GrossAmount: total,
-			},
-			CustomerDetails: struct {
-				FirstName string `json:"first_name"`
-				LastName  string `json:"last_name"`
-				Email     string `json:"email"`
-			}{
-				FirstName: checkoutRequest.Name,
-				LastName:  checkoutRequest.Name,
-				Email:     checkoutRequest.Email,
-			},
-			QRIS: struct {
-				Acquirer string `json:"acquirer"`
-			}{
-				Acquirer: "gopay", // Set acquirer to 'gopay', or whatever is needed
-			},
-		}
-
-		// Create transaction with Midtrans API
-		responseCreateTransactionMidtrans, errorResponse, err := services.CreateTransaction(midtransPayload)
-		if errorResponse != nil || err != nil {
-			statusCode, _ := strconv.Atoi(errorResponse.StatusCode)
-			c.JSON(statusCode, gin.H{"error": "Internal Server Error: Payment"})
-			return
-		}
-
-		midtransResponse = responseCreateTransactionMidtrans
-	}
-
-	// Create order in database
-	if err := db.Create(&order).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating order"})
-		return
-	}
-
-	// Initialize payment record
-	payment := models.Payment{
-		OrderID:              order.ID,
-		PaymentMethod:        paymentMethod.Name,
-		PaymentAccountNumber: paymentMethod.AccountNumber,
-		PaymentAccountName:   paymentMethod.AccountName,
-		PaymentStatus:        "pending",
-		PaymentCreateDate:    time.Now().Format("2006-01-02 15:04:05"),
-		TransactionCode:      fmt.Sprintf("TXN%d", order.ID),
-	}
-
-	// Ensure midtransResponse is not nil before accessing it
-	if payment.PaymentMethod == "QRIS" && midtransResponse != nil {
-		payment.PaymentQRCodeURL = midtransResponse.Actions[0].URL
-		payment.PaymentTransactionID = midtransResponse.TransactionID
-		payment.PaymentExpiredDate = midtransResponse.ExpiryTime
-	}
-
-	// Create order details
-	for i, product := range products {
-		quantity := productQuantities[product.ID]
-
-		orderDetail := models.OrderDetail{
-			OrderID:       order.ID,
-			MenuID:        product.ID,
-			Quantity:      quantity,
-			Notes:         checkoutRequest.Products[i].Notes,
-			SubtotalPrice: product.Price * float64(quantity),
-		}
-		if err := db.Create(&orderDetail).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating order details"})
-			return
-		}
-	}
-
-	// Create payment record in database
-	if err := db.Create(&payment).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating payment"})
+	order, err := orders.Checkout(c.Request.Context(), checkoutRequest)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error creating order"))
 		return
 	}
 
-	paymentDetails := models.PaymentDetails{
-		PaymentAccountNumber: payment.PaymentAccountNumber,
-		PaymentAccountName:   payment.PaymentAccountName,
-		PaymentMethod:        payment.PaymentMethod,
-		PaymentStatus:        payment.PaymentStatus,
-	}
-
-	// Set the PaymentExpiredTime based on the payment method
-	if payment.PaymentMethod == "BCA" {
-		paymentDetails.PaymentExpiredTime = (time.Minute * 10).Milliseconds()
-		paymentDetails.PaymentMethod = "bank"
-	} else if payment.PaymentMethod == "QRIS" {
-		paymentDetails.PaymentExpiredTime = (time.Minute * 15).Milliseconds()
-		paymentDetails.PaymentMethod = "qris"
-	}
-
-	// Ensure midtransResponse is not nil
-	if midtransResponse != nil {
-		paymentDetails.QRImageURL = midtransResponse.Actions[0].URL
-	}
-
-	// Build response
-	checkoutResponse := models.CheckoutResponse{
-		Name:            checkoutRequest.Name,
-		Email:           checkoutRequest.Email,
-		ProductDetails:  products,
-		Total:           total,
-		PaymentDetails:  paymentDetails,
-		TransactionCode: payment.TransactionCode,
-	}
-
-	// Sending Email
-	defer helpers.SendMail(checkoutResponse)
-
 	// Respond to client
 	c.JSON(http.StatusOK, models.ResponseSuccessWithData{
 		Status:  "OK",
-		Message: "Successfully created transaction",
+		Message: tr(c, "Successfully created transaction"),
 		Code:    http.StatusOK,
 		Data: gin.H{
 			"order_id":         order.ID,
@@ -282,156 +64,110 @@ func HandleCheckout(c *gin.Context, db *gorm.DB) {
 	})
 }
 
-func GetMenu(c *gin.Context, db *gorm.DB) {
+func GetMenu(c *gin.Context, menus *services.MenuService) {
 	category := c.DefaultQuery("category", "")
 
-	var menu []models.Menu
-
-	if category != "" {
-		// Fetch menu items with a specific category
-		if err := db.Joins("JOIN categories ON categories.id = menus.category_id").
-			Where("categories.category_name = ?", category).
-			Find(&menu).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu items by category"})
-			return
-		}
-	} else {
-		// Fetch all menu items
-		if err := db.Find(&menu).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch all menu items"})
-			return
+	menu, err := menus.List(c.Request.Context(), category)
+	if err != nil {
+		if category != "" {
+			apperror.Abort(c, apperror.Wrap(err, "Failed to fetch menu items by category"))
+		} else {
+			apperror.Abort(c, apperror.Wrap(err, "Failed to fetch all menu items"))
 		}
+		return
 	}
 
 	c.JSON(http.StatusOK, menu)
 }
 
-func GetMenuByID(c *gin.Context, db *gorm.DB) {
+func GetMenuByID(c *gin.Context, menus *services.MenuService) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid params id"})
+		apperror.Abort(c, apperror.Invalid("Invalid params id"))
 		return
 	}
 
-	var menu models.Menu
-	if err := db.Where("id = ?", id).First(&menu).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Order not found",
-			})
-		} else {
-			log.Println("Error fetching order:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to fetch order",
-				"details": err.Error(),
-			})
-		}
+	menu, err := menus.Get(c.Request.Context(), id)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Failed to fetch menu items"))
 		return
 	}
 
 	c.JSON(http.StatusOK, menu)
 }
 
-func GetCategories(c *gin.Context, db *gorm.DB) {
-	var categories []models.Category
-
-	if err := db.Find(&categories).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching all categories"})
+func GetCategories(c *gin.Context, categories *services.CategoryService) {
+	list, err := categories.List(c.Request.Context())
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error fetching all categories"))
 		return
 	}
 
-	c.JSON(http.StatusOK, categories)
+	c.JSON(http.StatusOK, list)
 }
 
-func GetCategoriesByID(c *gin.Context, db *gorm.DB) {
+func GetCategoriesByID(c *gin.Context, categories *services.CategoryService) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid params id"})
+		apperror.Abort(c, apperror.Invalid("Invalid params id"))
 		return
 	}
 
-	var category models.Category
-	if err := db.Where("id = ?", id).First(&category).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Order not found",
-			})
-		} else {
-			log.Println("Error fetching order:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to fetch order",
-				"details": err.Error(),
-			})
-		}
+	category, err := categories.Get(c.Request.Context(), id)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error fetching category"))
 		return
 	}
 
 	c.JSON(http.StatusOK, category)
 }
 
-func GetPaymentMethods(c *gin.Context, db *gorm.DB) {
-	var paymentMethods []models.PaymentMethod
-
-	if err := db.Find(&paymentMethods).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching all payment methods"})
+func GetPaymentMethods(c *gin.Context, payments *services.PaymentService) {
+	paymentMethods, err := payments.ListMethods(c.Request.Context())
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error fetching all payment methods"))
 		return
 	}
 
 	c.JSON(http.StatusOK, paymentMethods)
 }
 
-func GetAllOrders(c *gin.Context, db *gorm.DB) {
-	var orders []models.Order
+func GetAllOrders(c *gin.Context, orders *services.OrderService) {
+	filter, err := orderFilterParams(c)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
 
-	// Preload relasi ke User dan OrderDetails
-	if err := db.Preload("Payment").Preload("OrderDetails.Menu").Find(&orders).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch orders",
-			"details": err.Error(),
-		})
+	list, err := orders.List(c.Request.Context(), filter)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Failed to fetch orders"))
 		return
 	}
 
-	c.JSON(http.StatusOK, orders)
+	c.JSON(http.StatusOK, list)
 }
 
-func GetOrderByID(c *gin.Context, db *gorm.DB) {
+func GetOrderByID(c *gin.Context, orders *services.OrderService, payments *services.PaymentService) {
 	orderIDStr := c.Param("id")
 	orderID, err := strconv.Atoi(orderIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid order ID",
-		})
+		apperror.Abort(c, apperror.Invalid("Invalid order ID"))
 		return
 	}
 
 	// Perbarui status order terlebih dahulu
-	_, err = CheckAndUpdateOrderStatus(orderID, db)
-	if err != nil {
-		log.Println("Error updating order status:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to update order status",
-			"details": err.Error(),
-		})
+	if _, err := payments.RefreshOrderStatus(c.Request.Context(), orderID); err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Failed to update order status"))
 		return
 	}
 
 	// Ambil data order setelah status diperbarui
-	var order models.Order
-	if err := db.Preload("OrderDetails").Preload("Payment").Preload("OrderDetails.Menu").First(&order, "id = ?", orderID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Order not found",
-			})
-		} else {
-			log.Println("Error fetching order:", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to fetch order",
-				"details": err.Error(),
-			})
-		}
+	order, err := orders.Get(c.Request.Context(), orderID)
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Failed to fetch order"))
 		return
 	}
 
@@ -439,31 +175,10 @@ func GetOrderByID(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, order)
 }
 
-func GetOrderByTransactionCode(c *gin.Context, db *gorm.DB) {
-	transactionCodeStr := c.Param("transactionCode")
-	var order models.Order
-
-	// Use Join to include Payment and filter by transaction code
-	err := db.Preload("OrderDetails.Menu"). // Preload nested relations
-						Preload("OrderDetails").
-						Preload("Payment").
-						Joins("JOIN payments ON payments.order_id = orders.id"). // Explicit join
-						Where("payments.transaction_code = ?", transactionCodeStr).
-						First(&order).Error
-
+func GetOrderByTransactionCode(c *gin.Context, orders *services.OrderService) {
+	order, err := orders.GetByTransactionCode(c.Request.Context(), c.Param("transactionCode"))
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// Return 404 if no order is found
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Order not found",
-			})
-		} else {
-			// Handle other database errors
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to fetch order",
-				"details": err.Error(),
-			})
-		}
+		apperror.Abort(c, apperror.Wrap(err, "Failed to fetch order"))
 		return
 	}
 
@@ -471,200 +186,216 @@ func GetOrderByTransactionCode(c *gin.Context, db *gorm.DB) {
 	c.JSON(http.StatusOK, order)
 }
 
-func UpdateCategory(c *gin.Context, db *gorm.DB) {
+// GetOrderReceipt renders the invoice of an order as a PDF. The route parameter is the
+// transaction code; it is named id only because gin requires one wildcard name per segment.
+func GetOrderReceipt(c *gin.Context, orders *services.OrderService, company utils.Company) {
+	order, err := orders.GetByTransactionCode(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Failed to fetch order"))
+		return
+	}
+
+	receipt, err := helpers.RenderReceiptPDF(helpers.ConvertOrderToInvoiceData(order, company))
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to render receipt"))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", "receipt-"+order.Payment.TransactionCode+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", receipt)
+}
+
+func UpdateCategory(c *gin.Context, categories *services.CategoryService) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid request id"})
+		apperror.Abort(c, apperror.Invalid("Invalid request id"))
 		return
 	}
 
 	var category models.Category
 	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		apperror.Abort(c, apperror.Validation(err))
 		return
 	}
 
-	if err := db.Model(&models.Category{}).Where("id = ?", id).Updates(category).Error; err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Error updating category"})
+	if err := categories.Update(c.Request.Context(), id, category); err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error updating category"))
 		return
 	}
 
 	c.JSON(http.StatusOK, models.ResponseSuccess{
 		Status:  "OK",
-		Message: "Successfully updated category",
+		Message: tr(c, "Successfully updated category"),
 		Code:    http.StatusOK,
 	})
 }
 
-func CreateCategory(c *gin.Context, db *gorm.DB) {
+// PatchCategory updates only the fields present in the body.
+func PatchCategory(c *gin.Context, categories *services.CategoryService) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid request id"))
+		return
+	}
+
+	var patch models.CategoryPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	if err := categories.Patch(c.Request.Context(), id, patch); err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error updating category"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully updated category"),
+		Code:    http.StatusOK,
+	})
+}
+
+func CreateCategory(c *gin.Context, categories *services.CategoryService) {
 	var category models.Category
 	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		apperror.Abort(c, apperror.Validation(err))
 		return
 	}
 
-	if err := db.Create(&category).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating new category"})
+	if err := categories.Create(c.Request.Context(), &category); err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error creating new category"))
 		return
 	}
 
 	c.JSON(http.StatusOK, models.ResponseSuccess{
 		Status:  "OK",
-		Message: "Successfully created category",
+		Message: tr(c, "Successfully created category"),
 		Code:    http.StatusOK,
 	})
 }
 
-func CreateNewProduct(c *gin.Context, db *gorm.DB) {
-	var menu models.Menu
-	if err := c.ShouldBindJSON(&menu); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+func CreateNewProduct(c *gin.Context, menus *services.MenuService) {
+	var request models.MenuRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
 		return
 	}
+	menu := request.Menu()
 
-	if err := db.Create(&menu).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating new menu"})
+	if err := menus.Create(c.Request.Context(), &menu); err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error creating new menu"))
 		return
 	}
 
 	c.JSON(http.StatusOK, models.ResponseSuccess{
 		Status:  "OK",
-		Message: "Successfully created product",
+		Message: tr(c, "Successfully created product"),
 		Code:    http.StatusOK,
 	})
 }
 
-func UpdateProduct(c *gin.Context, db *gorm.DB) {
+func UpdateProduct(c *gin.Context, menus *services.MenuService) {
 	// Parse and validate the product ID from the URL
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		apperror.Abort(c, apperror.Invalid("Invalid product ID"))
 		return
 	}
 
-	// Check if the product exists
-	var existingMenu models.Menu
-	if err := db.First(&existingMenu, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error: " + err.Error()})
-		}
+	// Bind and validate the JSON payload
+	var request models.MenuRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
 		return
 	}
+	menu := request.Menu()
+	menu.ID = id
 
-	// Bind and validate the JSON payload
-	var menu models.Menu
-	if err := c.ShouldBindJSON(&menu); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := menus.Update(c.Request.Context(), menu); err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error updating product"))
 		return
 	}
-	log.Printf("Received payload: %+v\n", menu)
 
-	// Update only specific fields
-	if err := db.Model(&existingMenu).Updates(map[string]interface{}{
-		"Name":        menu.Name,
-		"Price":       menu.Price,
-		"Description": menu.Description,
-		"CategoryID":  menu.CategoryID,
-		"ImageURL":    menu.ImageURL,
-		"Rating":      menu.Rating,
-		"IsAvailable": menu.IsAvailable,
-	}).Error; err != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Error updating product: " + err.Error()})
+	// Respond with success
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully updated product"),
+		Code:    http.StatusOK,
+	})
+}
+
+// PatchProduct updates only the fields present in the body.
+func PatchProduct(c *gin.Context, menus *services.MenuService) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid product ID"))
+		return
+	}
+
+	var patch models.MenuPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	if err := menus.Patch(c.Request.Context(), id, patch); err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Error updating product"))
 		return
 	}
 
-	// Respond with success
 	c.JSON(http.StatusOK, models.ResponseSuccess{
 		Status:  "OK",
-		Message: "Successfully updated product",
+		Message: tr(c, "Successfully updated product"),
 		Code:    http.StatusOK,
 	})
 }
 
-func CheckOrderStatusByID(c *gin.Context, db *gorm.DB) {
+func CheckOrderStatusByID(c *gin.Context, payments *services.PaymentService) {
 	orderIDStr := c.Param("id")
 	orderID, err := strconv.Atoi(orderIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid order ID",
-		})
+		apperror.Abort(c, apperror.Invalid("Invalid order ID"))
 		return
 	}
 
 	// Perbarui status order
-	orderStatus, err := CheckAndUpdateOrderStatus(orderID, db)
+	orderStatus, err := payments.RefreshOrderStatus(c.Request.Context(), orderID)
 	if err != nil {
-		log.Println("Error updating order status:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to update order status",
-			"details": err.Error(),
-		})
+		apperror.Abort(c, apperror.Wrap(err, "Failed to update order status"))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":      "Order status updated successfully",
+		"message":      tr(c, "Order status updated successfully"),
 		"order_status": orderStatus,
 	})
 }
 
-func CheckAndUpdateOrderStatus(orderID int, db *gorm.DB) (string, error) {
-	// Cari data payment berdasarkan order_id
-	var payment models.Payment
-	if err := db.Where("order_id = ?", orderID).First(&payment).Error; err != nil {
-		return "", fmt.Errorf("failed to fetch payment: %w", err)
-	}
-
-	// Periksa transaksi menggunakan layanan eksternal
-	result, errResp, errCheckTrx := services.CheckTransaction(payment.PaymentTransactionID)
-	if errResp != nil || errCheckTrx != nil {
-		return "", fmt.Errorf("error checking transaction: %v %v", errResp, errCheckTrx)
-	}
-
-	// Tentukan status order berdasarkan status transaksi
-	var orderStatus string
-	switch result.TransactionStatus {
-	case "authorize":
-		orderStatus = "authorized"
-	case "capture":
-		orderStatus = "captured"
-	case "settlement":
-		orderStatus = "success"
-	case "deny":
-		orderStatus = "denied"
-	case "pending":
-		orderStatus = "pending"
-	case "cancel":
-		orderStatus = "canceled"
-	case "refund":
-		orderStatus = "refunded"
-	case "partial_refund":
-		orderStatus = "partially_refunded"
-	case "chargeback":
-		orderStatus = "charged_back"
-	case "partial_chargeback":
-		orderStatus = "partially_charged_back"
-	case "expire":
-		orderStatus = "expired"
-	case "failure":
-		orderStatus = "failed"
-	default:
-		orderStatus = "unknown"
-	}
-
-	// Perbarui status order di database
-	if err := db.Model(&models.Order{}).Where("id = ?", orderID).Update("order_status", orderStatus).Error; err != nil {
-		return "", fmt.Errorf("failed to update order status: %w", err)
-	}
-
-	// Perbarui status payment di database
-	if err := db.Model(&models.Payment{}).Where("order_id = ?", orderID).Update("payment_status", orderStatus).Error; err != nil {
-		return "", fmt.Errorf("failed to update payment status: %w", err)
-	}
-
-	return orderStatus, nil
+// UpdateOrderStatus lets staff move an order through its lifecycle and notifies the customer.
+func UpdateOrderStatus(c *gin.Context, orders *services.OrderService) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid order ID"))
+		return
+	}
+
+	var request models.OrderStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	if err := orders.UpdateOrderStatus(c.Request.Context(), orderID, request.OrderStatus); err != nil {
+		apperror.Abort(c, apperror.Wrap(err, "Failed to update order status"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully updated order status"),
+		Code:    http.StatusOK,
+	})
 }
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// openTestDB returns an in-memory SQLite database with the tables handlers query.
+// Handlers that rely on PostgreSQL only syntax cannot be tested against it.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own
+	db.DB().SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.AutoMigrate(&models.Menu{}, &models.Order{}, &models.OrderDetail{}, &models.Payment{}, &models.Review{}).Error; err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// serve runs handler mounted at pattern for one request, behind the middleware main
+// mounts in front of every handler.
+func serve(handler gin.HandlerFunc, method, pattern, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	i18n.UseJSONFieldNames()
+	r := gin.New()
+	r.Use(i18n.Middleware(), apperror.Middleware())
+	r.Handle(method, pattern, handler)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
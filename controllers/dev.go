@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCapturedEmails lists emails kept by the capture transport. Development only.
+func GetCapturedEmails(c *gin.Context, mailer *helpers.CaptureMailer) {
+	c.JSON(http.StatusOK, mailer.Messages())
+}
+
+// GetCapturedEmail renders the HTML body of a captured email so it can be previewed in a browser.
+func GetCapturedEmail(c *gin.Context, mailer *helpers.CaptureMailer) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid email ID"))
+		return
+	}
+
+	msg, ok := mailer.Message(id)
+	if !ok {
+		apperror.Abort(c, apperror.NotFound("Email not found"))
+		return
+	}
+
+	if c.Query("format") == "text" {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(msg.Text))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(msg.HTML))
+}
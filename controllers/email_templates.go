@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/services"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/jinzhu/gorm"
+)
+
+// GetEmailTemplates lists every template with the stored version currently in use.
+func GetEmailTemplates(c *gin.Context, db *gorm.DB) {
+	var latest []struct {
+		Name     string
+		Language string
+		Version  int
+	}
+	if err := db.Model(&models.EmailTemplate{}).
+		Select("name, language, MAX(version) AS version").
+		Group("name, language").
+		Scan(&latest).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to fetch email templates"))
+		return
+	}
+
+	summaries := make([]models.EmailTemplateSummary, 0)
+	for _, name := range helpers.TemplateNames() {
+		summary := models.EmailTemplateSummary{
+			Name:           name,
+			ActiveVersions: map[string]int{i18n.Indonesian: 0, i18n.English: 0},
+		}
+		for _, row := range latest {
+			if row.Name == name {
+				summary.ActiveVersions[row.Language] = row.Version
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+
+	c.JSON(http.StatusOK, summaries)
+}
+
+// GetEmailTemplateVersions lists the stored versions of a template, newest first,
+// optionally for one language. The built-in template is included for reference.
+func GetEmailTemplateVersions(c *gin.Context, db *gorm.DB) {
+	name := c.Param("name")
+	builtin, ok := helpers.BuiltinTemplate(name)
+	if !ok {
+		apperror.Abort(c, apperror.NotFound("Email template not found"))
+		return
+	}
+
+	query := db.Where("name = ?", name).Order("language, version DESC")
+	if lang := c.Query("language"); lang != "" {
+		query = query.Where("language = ?", lang)
+	}
+
+	var versions []models.EmailTemplate
+	if err := query.Find(&versions).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to fetch email templates"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":     name,
+		"builtin":  builtin,
+		"versions": versions,
+	})
+}
+
+// UpdateEmailTemplate saves a new version of a template after checking that it parses
+// and renders. The new version is used for every email queued from now on.
+func UpdateEmailTemplate(c *gin.Context, db *gorm.DB) {
+	var request models.EmailTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	set := helpers.TemplateSet{Subject: request.Subject, HTML: request.HTML, Text: request.Text}
+	template, err := services.SaveEmailTemplate(db, c.Param("name"), request.Language, set, staffEmail(c))
+	if err != nil {
+		var templateErr *services.TemplateError
+		switch {
+		case errors.Is(err, services.ErrUnknownTemplate):
+			apperror.Abort(c, apperror.NotFound("Email template not found"))
+		case errors.As(err, &templateErr):
+			apperror.Abort(c, apperror.Invalid("Template is invalid: %v", templateErr.Err))
+		default:
+			apperror.Abort(c, apperror.Internal(err, "Error saving email template"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccessWithData{
+		Status:  "OK",
+		Message: tr(c, "Successfully saved email template"),
+		Code:    http.StatusOK,
+		Data:    template,
+	})
+}
+
+// PreviewEmailTemplate renders a draft, or the template in use when the body has no html,
+// against an existing order or a sample one. Pass ?format=html or ?format=text to get the
+// rendered body instead of JSON.
+func PreviewEmailTemplate(c *gin.Context, db *gorm.DB, company utils.Company) {
+	name := c.Param("name")
+	if _, ok := helpers.BuiltinTemplate(name); !ok {
+		apperror.Abort(c, apperror.NotFound("Email template not found"))
+		return
+	}
+
+	// The body is optional, an empty one previews the template in use
+	var request models.EmailTemplatePreviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+	lang := i18n.Resolve(request.Language, i18n.FromContext(c))
+
+	data := helpers.SampleInvoiceData(lang, c.DefaultQuery("payment_method", "qris"))
+	if request.OrderID != 0 {
+		var order models.Order
+		if err := db.Preload("Payment").Preload("OrderDetails.Menu").First(&order, request.OrderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperror.Abort(c, apperror.NotFound("Order not found"))
+			} else {
+				apperror.Abort(c, apperror.Internal(err, "Failed to fetch order"))
+			}
+			return
+		}
+		data = helpers.ConvertOrderToInvoiceData(order, company)
+		if request.Language != "" {
+			data.Language = request.Language
+		}
+	}
+
+	var (
+		rendered helpers.RenderedEmail
+		err      error
+	)
+	if request.HTML != "" {
+		set := helpers.TemplateSet{Subject: request.Subject, HTML: request.HTML, Text: request.Text}
+		rendered, err = helpers.RenderTemplateSet(name, set, data)
+		if err != nil {
+			apperror.Abort(c, apperror.Invalid("Template is invalid: %v", err))
+			return
+		}
+	} else if rendered, err = services.RenderEmailTemplate(db, name, data); err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error rendering email template"))
+		return
+	}
+
+	switch c.Query("format") {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(rendered.HTML))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(rendered.Text))
+	default:
+		c.JSON(http.StatusOK, gin.H{
+			"subject": rendered.Subject,
+			"html":    rendered.HTML,
+			"text":    rendered.Text,
+		})
+	}
+}
+
+// staffEmail returns the email of the logged in staff member from the JWT claims.
+func staffEmail(c *gin.Context) string {
+	claims, ok := c.Get("claims")
+	if !ok {
+		return ""
+	}
+	if mapClaims, ok := claims.(jwt.MapClaims); ok {
+		email, _ := mapClaims["email"].(string)
+		return email
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/services"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// GetEmails lists outbox emails, by default the ones that failed or were dead-lettered.
+func GetEmails(c *gin.Context, db *gorm.DB) {
+	statuses := []string{models.EmailStatusFailed, models.EmailStatusDead}
+	if status := c.Query("status"); status != "" {
+		statuses = []string{status}
+	}
+
+	limit, offset := paginationParams(c)
+
+	var emails []models.EmailOutbox
+	if err := db.Where("status IN (?)", statuses).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&emails).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to fetch emails"))
+		return
+	}
+
+	c.JSON(http.StatusOK, emails)
+}
+
+// ResendEmail queues a failed or dead-lettered email for another round of attempts.
+func ResendEmail(c *gin.Context, db *gorm.DB) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid email ID"))
+		return
+	}
+
+	if err := services.ResendEmail(db, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperror.Abort(c, apperror.NotFound("No failed email with this ID"))
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "Error resending email"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Email queued for resending"),
+		Code:    http.StatusOK,
+	})
+}
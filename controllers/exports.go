@@ -0,0 +1,318 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/xuri/excelize/v2"
+)
+
+// Export formats accepted by the export handlers.
+const (
+	ExportCSV  = "csv"
+	ExportXLSX = "xlsx"
+)
+
+// orderFilterParams reads the status, email, payment_method, from and to (YYYY-MM-DD,
+// inclusive) query parameters shared by listing and exports.
+func orderFilterParams(c *gin.Context) (repositories.OrderFilter, error) {
+	filter := repositories.OrderFilter{
+		Status:        c.Query("status"),
+		Email:         c.Query("email"),
+		PaymentMethod: c.Query("payment_method"),
+		From:          c.Query("from"),
+		To:            c.Query("to"),
+	}
+	if filter.From != "" {
+		if _, err := time.Parse("2006-01-02", filter.From); err != nil {
+			return filter, i18n.Errorf("invalid from date, expected YYYY-MM-DD")
+		}
+	}
+	if filter.To != "" {
+		if _, err := time.Parse("2006-01-02", filter.To); err != nil {
+			return filter, i18n.Errorf("invalid to date, expected YYYY-MM-DD")
+		}
+	}
+	return filter, nil
+}
+
+var orderExportHeader = []string{
+	"order_id", "order_date", "name", "email", "order_status",
+	"payment_method", "payment_status", "transaction_code",
+	"menu_id", "menu_name", "quantity", "unit_price", "discount", "subtotal", "notes",
+	"order_subtotal", "order_discount", "order_tax", "order_total",
+}
+
+// rowWriter lets the order export share one streaming loop between CSV and XLSX.
+type rowWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// ExportOrders streams one row per order line, reading from a database cursor
+// so large date ranges are never held in memory.
+func ExportOrders(c *gin.Context, db *gorm.DB, format string) {
+	filter, err := orderFilterParams(c)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	rows, err := repositories.ApplyOrderFilter(db.Table("orders"), filter).
+		Select(`orders.id, orders.order_date, orders.name, orders.email, orders.order_status,
+			COALESCE(payments.payment_method, ''), COALESCE(payments.payment_status, ''), COALESCE(payments.transaction_code, ''),
+			COALESCE(order_details.menu_id, 0), COALESCE(menus.name, ''), COALESCE(order_details.quantity, 0),
+			COALESCE(order_details.unit_price, order_details.subtotal_price / NULLIF(order_details.quantity, 0), 0),
+			COALESCE(order_details.discount, 0), COALESCE(order_details.subtotal_price, 0), COALESCE(order_details.notes, ''),
+			orders.total_price, COALESCE(orders.discount, 0), COALESCE(orders.tax, orders.total_price * ?)`, models.TaxRate).
+		Joins("LEFT JOIN payments ON payments.order_id = orders.id").
+		Joins("LEFT JOIN order_details ON order_details.order_id = orders.id").
+		Joins("LEFT JOIN menus ON menus.id = order_details.menu_id").
+		Order("orders.id, order_details.id").
+		Rows()
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to export orders"))
+		return
+	}
+	defer rows.Close()
+
+	writer, err := newRowWriter(c, format, "orders")
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	header := make([]interface{}, len(orderExportHeader))
+	for i, h := range orderExportHeader {
+		header[i] = h
+	}
+	if err := writer.WriteRow(header); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error writing export header", "error", err)
+		return
+	}
+
+	for rows.Next() {
+		var (
+			orderID, menuID, quantity                                  int
+			orderDate, name, email, orderStatus                        string
+			paymentMethod, paymentStatus, transactionCode, menu, notes string
+			unitPrice, lineDiscount, subtotal                          float64
+			orderSubtotal, discount, tax                               float64
+		)
+		if err := rows.Scan(&orderID, &orderDate, &name, &email, &orderStatus,
+			&paymentMethod, &paymentStatus, &transactionCode,
+			&menuID, &menu, &quantity, &unitPrice, &lineDiscount, &subtotal, &notes,
+			&orderSubtotal, &discount, &tax); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Error scanning export row", "error", err)
+			return
+		}
+
+		if err := writer.WriteRow([]interface{}{
+			orderID, orderDate, name, email, orderStatus,
+			paymentMethod, paymentStatus, transactionCode,
+			menuID, menu, quantity, unitPrice, lineDiscount, subtotal, notes,
+			orderSubtotal, discount, tax, orderSubtotal + tax,
+		}); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Error writing export row", "error", err)
+			return
+		}
+	}
+	if err := rows.Err(); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error iterating export rows", "error", err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error finishing export", "error", err)
+	}
+}
+
+// ExportSalesReport exports a sales report grouped by group_by (day, week, month,
+// menu, category or payment_method) with the same parameters as the report endpoints.
+func ExportSalesReport(c *gin.Context, db *gorm.DB, format string) {
+	groupBy := c.DefaultQuery("group_by", ReportByDay)
+
+	params, err := parseReportParams(c, groupBy)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	if _, _, err := reportQuery(params, groupBy); err != nil || groupBy == "" {
+		apperror.Abort(c, apperror.Invalid("group_by must be one of day, week, month, menu, category or payment_method"))
+		return
+	}
+
+	report, err := buildSalesReport(db, params)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to build sales report"))
+		return
+	}
+
+	writer, err := newRowWriter(c, format, "sales-"+groupBy)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	figuresHeader := []interface{}{"orders", "items", "gross_sales", "discounts", "refunds", "net_sales", "tax", "average_order_value"}
+	figures := func(f models.SalesFigures) []interface{} {
+		return []interface{}{f.Orders, f.Items, f.GrossSales, f.Discounts, f.Refunds, f.NetSales, f.Tax, f.AverageOrderValue}
+	}
+
+	var keyHeader []interface{}
+	var keys func(row models.SalesReportRow) []interface{}
+	switch groupBy {
+	case ReportByMenu:
+		keyHeader = []interface{}{"menu_id", "menu_name"}
+		keys = func(row models.SalesReportRow) []interface{} { return []interface{}{row.MenuID, row.MenuName} }
+	case ReportByCategory:
+		keyHeader = []interface{}{"category_id", "category_name"}
+		keys = func(row models.SalesReportRow) []interface{} { return []interface{}{row.CategoryID, row.CategoryName} }
+	case ReportByPaymentMethod:
+		keyHeader = []interface{}{"payment_method"}
+		keys = func(row models.SalesReportRow) []interface{} { return []interface{}{row.PaymentMethod} }
+	default:
+		keyHeader = []interface{}{"period"}
+		keys = func(row models.SalesReportRow) []interface{} { return []interface{}{row.Period} }
+	}
+
+	if err := writer.WriteRow(append(keyHeader, figuresHeader...)); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error writing export header", "error", err)
+		return
+	}
+	for _, row := range report.Rows {
+		if err := writer.WriteRow(append(keys(row), figures(row.SalesFigures)...)); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Error writing export row", "error", err)
+			return
+		}
+	}
+
+	total := make([]interface{}, len(keyHeader))
+	total[0] = "TOTAL"
+	for i := 1; i < len(total); i++ {
+		total[i] = ""
+	}
+	if err := writer.WriteRow(append(total, figures(report.Total)...)); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error writing export row", "error", err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error finishing export", "error", err)
+	}
+}
+
+// newRowWriter sets the download headers and returns a writer for the requested format.
+func newRowWriter(c *gin.Context, format, name string) (rowWriter, error) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+
+	switch format {
+	case ExportCSV:
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Status(http.StatusOK)
+		return &csvRowWriter{c: c, w: csv.NewWriter(c.Writer)}, nil
+	case ExportXLSX:
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter("Sheet1")
+		if err != nil {
+			return nil, err
+		}
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		return &xlsxRowWriter{c: c, f: f, sw: sw}, nil
+	default:
+		return nil, i18n.Errorf("unsupported export format %q", format)
+	}
+}
+
+// spreadsheetCell keeps a text value from being read as a formula when the export is
+// opened in a spreadsheet, customers control names, emails and notes. Other values
+// are returned unchanged.
+func spreadsheetCell(v interface{}) interface{} {
+	text, ok := v.(string)
+	if !ok || text == "" {
+		return v
+	}
+	switch text[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + text
+	}
+	return v
+}
+
+type csvRowWriter struct {
+	c     *gin.Context
+	w     *csv.Writer
+	count int
+}
+
+func (w *csvRowWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := spreadsheetCell(v).(type) {
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', 2, 64)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	if err := w.w.Write(record); err != nil {
+		return err
+	}
+
+	// Flush periodically so the client receives rows as they are read
+	w.count++
+	if w.count%500 == 0 {
+		w.w.Flush()
+		w.c.Writer.Flush()
+	}
+	return w.w.Error()
+}
+
+func (w *csvRowWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// xlsxRowWriter uses excelize's stream writer, which spills rows to a temporary
+// file instead of keeping the whole sheet in memory.
+type xlsxRowWriter struct {
+	c   *gin.Context
+	f   *excelize.File
+	sw  *excelize.StreamWriter
+	row int
+}
+
+func (w *xlsxRowWriter) WriteRow(values []interface{}) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		cells[i] = spreadsheetCell(v)
+	}
+	return w.sw.SetRow(cell, cells)
+}
+
+func (w *xlsxRowWriter) Close() error {
+	defer w.f.Close()
+	if err := w.sw.Flush(); err != nil {
+		return err
+	}
+	w.c.Status(http.StatusOK)
+	return w.f.Write(w.c.Writer)
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSpreadsheetCell(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  interface{}
+	}{
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+62 812", "'+62 812"},
+		{"-1+2", "'-1+2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"Budi", "Budi"},
+		{"", ""},
+		{-5000.0, -5000.0},
+		{-1, -1},
+	}
+	for _, tt := range tests {
+		if got := spreadsheetCell(tt.value); got != tt.want {
+			t.Errorf("spreadsheetCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func seedExportOrders(t *testing.T) func(c *gin.Context, format string) {
+	db := openTestDB(t)
+	db.Create(&models.Menu{ID: 1, Name: "Carbonara", Price: 50000})
+	db.Create(&models.Order{ID: 1, OrderDate: "2024-12-23 09:00:00", Name: "=HYPERLINK(\"http://evil\",\"Budi\")", Email: "budi@example.com", OrderStatus: "success", TotalPrice: 80000, Discount: 20000, Tax: 8000})
+	db.Create(&models.Payment{OrderID: 1, PaymentMethod: "BCA", PaymentStatus: "success", TransactionCode: "TXN1"})
+	db.Create(&models.OrderDetail{OrderID: 1, MenuID: 1, Quantity: 2, UnitPrice: 40000, Discount: 20000, SubtotalPrice: 80000, Notes: "@SUM(A1)"})
+	db.Create(&models.Order{ID: 2, OrderDate: "2024-11-01 09:00:00", Name: "Siti", Email: "siti@example.com", OrderStatus: "success", TotalPrice: 50000})
+	return func(c *gin.Context, format string) { ExportOrders(c, db, format) }
+}
+
+func TestExportOrdersCSV(t *testing.T) {
+	export := seedExportOrders(t)
+	w := serve(func(c *gin.Context) { export(c, ExportCSV) }, http.MethodGet, "/exports/orders.csv", "/exports/orders.csv?from=2024-12-01&to=2024-12-31", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Fatalf("got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if len(records) != 2 || len(records[0]) != len(orderExportHeader) {
+		t.Fatalf("got %d rows, want the header and the one order line in range: %v", len(records), records)
+	}
+	row := map[string]string{}
+	for i, column := range orderExportHeader {
+		row[column] = records[1][i]
+	}
+	if row["name"] != "'=HYPERLINK(\"http://evil\",\"Budi\")" || row["notes"] != "'@SUM(A1)" {
+		t.Errorf("formulas not neutralised: name %q notes %q", row["name"], row["notes"])
+	}
+	// The line is priced after its price rule, not at the menu price
+	if row["unit_price"] != "40000.00" || row["discount"] != "20000.00" || row["order_total"] != "88000.00" || row["menu_name"] != "Carbonara" {
+		t.Errorf("got %v", row)
+	}
+}
+
+func TestExportOrdersXLSX(t *testing.T) {
+	export := seedExportOrders(t)
+	w := serve(func(c *gin.Context) { export(c, ExportXLSX) }, http.MethodGet, "/exports/orders.xlsx", "/exports/orders.xlsx", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d %s", w.Code, w.Body.String())
+	}
+
+	f, err := excelize.OpenReader(w.Body)
+	if err != nil {
+		t.Fatalf("open XLSX: %v", err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatalf("GetRows: %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "order_id" {
+		t.Fatalf("got rows %v, want the header and both orders", rows)
+	}
+	if rows[1][2] != "'=HYPERLINK(\"http://evil\",\"Budi\")" {
+		t.Errorf("formula not neutralised: %q", rows[1][2])
+	}
+	if formula, _ := f.GetCellFormula("Sheet1", "C2"); formula != "" {
+		t.Errorf("name stored as formula %q", formula)
+	}
+}
+
+func TestExportOrdersRejectsBadInput(t *testing.T) {
+	export := seedExportOrders(t)
+	if w := serve(func(c *gin.Context) { export(c, ExportCSV) }, http.MethodGet, "/exports/orders.csv", "/exports/orders.csv?from=23-12-2024", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("got %d for a bad date, want 400", w.Code)
+	}
+	if w := serve(func(c *gin.Context) { export(c, "ods") }, http.MethodGet, "/exports/orders.ods", "/exports/orders.ods", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("got %d for an unknown format, want 400", w.Code)
+	}
+}
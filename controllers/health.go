@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/migrations"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// Healthz reports that the process is up. It never touches dependencies, so a slow
+// database does not get the machine restarted.
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the instance can serve traffic: the database answers, its schema
+// matches this build, and the server is not draining for shutdown.
+func Readyz(c *gin.Context, db *gorm.DB, draining *atomic.Bool) {
+	checks := gin.H{}
+	ready := true
+
+	if draining.Load() {
+		checks["server"] = "shutting down"
+		ready = false
+	} else {
+		checks["server"] = "ok"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+	if err := db.DB().PingContext(ctx); err != nil {
+		// The driver error names hosts and users, it only goes to the logs
+		slog.WarnContext(ctx, "Database ping failed", "error", err)
+		checks["database"] = "unavailable"
+		ready = false
+	} else {
+		checks["database"] = "ok"
+
+		if err := migrations.Check(ctx, db.DB()); err != nil {
+			checks["migrations"] = err.Error()
+			ready = false
+		} else {
+			checks["migrations"] = "ok"
+		}
+	}
+
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{"ready": ready, "checks": checks})
+}
+
+// GetVersion returns the version and commit of the running build.
+func GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.GetBuildInfo())
+}
@@ -0,0 +1,11 @@
+package controllers
+
+import (
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// tr translates an API message into the language of the current request.
+func tr(c *gin.Context, message string, args ...interface{}) string {
+	return i18n.T(i18n.FromContext(c), message, args...)
+}
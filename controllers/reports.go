@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// Report groupings accepted by buildSalesReport.
+const (
+	ReportByDay           = "day"
+	ReportByWeek          = "week"
+	ReportByMonth         = "month"
+	ReportByMenu          = "menu"
+	ReportByCategory      = "category"
+	ReportByPaymentMethod = "payment_method"
+)
+
+// reportOrderStatuses are the orders counted in reports: paid ones plus those refunded afterwards.
+// Partial refunds have no stored amount, so they count as fully paid.
+var reportOrderStatuses = append([]string{"refunded", "partially_refunded"}, models.PaidOrderStatuses...)
+
+type reportParams struct {
+	From     time.Time
+	To       time.Time // Inclusive last day
+	Timezone string
+	GroupBy  string
+}
+
+// parseReportParams reads from, to (YYYY-MM-DD, inclusive) and tz (IANA name).
+// The range defaults to the last 30 days in the given timezone.
+func parseReportParams(c *gin.Context, groupBy string) (reportParams, error) {
+	tz := c.DefaultQuery("tz", "Asia/Jakarta")
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return reportParams{}, i18n.Errorf("invalid timezone %q", tz)
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	params := reportParams{
+		From:     today.AddDate(0, 0, -29),
+		To:       today,
+		Timezone: tz,
+		GroupBy:  groupBy,
+	}
+
+	if from := c.Query("from"); from != "" {
+		if params.From, err = time.ParseInLocation("2006-01-02", from, loc); err != nil {
+			return reportParams{}, i18n.Errorf("invalid from date, expected YYYY-MM-DD")
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if params.To, err = time.ParseInLocation("2006-01-02", to, loc); err != nil {
+			return reportParams{}, i18n.Errorf("invalid to date, expected YYYY-MM-DD")
+		}
+	}
+	if params.To.Before(params.From) {
+		return reportParams{}, i18n.Errorf("to must not be before from")
+	}
+
+	return params, nil
+}
+
+// Each base yields one row per order or per order line with uniform money columns,
+// so every grouping aggregates the same way. Lines carry their own discount, only lines
+// stored without one get a share of the order discount. Line tax is prorated, refunded
+// orders owe no tax.
+const orderReportBase = `
+	SELECT o.id AS order_id, o.local_date, pay.payment_method, o.items,
+		o.total_price + o.discount AS gross,
+		o.discount,
+		CASE WHEN o.order_status = 'refunded' THEN o.total_price ELSE 0 END AS refund,
+		CASE WHEN o.order_status = 'refunded' THEN 0 ELSE o.tax END AS tax
+	FROM in_range o
+	LEFT JOIN payments pay ON pay.order_id = o.id`
+
+const lineReportBase = `
+	SELECT o.id AS order_id, o.local_date, d.menu_id, d.quantity AS items,
+		d.subtotal_price + COALESCE(d.discount, CASE WHEN o.total_price > 0 THEN o.discount * d.subtotal_price / o.total_price ELSE 0 END) AS gross,
+		COALESCE(d.discount, CASE WHEN o.total_price > 0 THEN o.discount * d.subtotal_price / o.total_price ELSE 0 END) AS discount,
+		CASE WHEN o.order_status = 'refunded' THEN d.subtotal_price ELSE 0 END AS refund,
+		CASE WHEN o.order_status = 'refunded' OR o.total_price <= 0 THEN 0 ELSE o.tax * d.subtotal_price / o.total_price END AS tax
+	FROM in_range o
+	JOIN order_details d ON d.order_id = o.id`
+
+const reportFigures = `
+	COUNT(DISTINCT b.order_id) AS orders,
+	COALESCE(SUM(b.items), 0) AS items,
+	COALESCE(SUM(b.gross), 0) AS gross_sales,
+	COALESCE(SUM(b.discount), 0) AS discounts,
+	COALESCE(SUM(b.refund), 0) AS refunds,
+	COALESCE(SUM(b.gross - b.discount - b.refund), 0) AS net_sales,
+	COALESCE(SUM(b.tax), 0) AS tax,
+	COALESCE(SUM(b.gross - b.discount) / NULLIF(COUNT(DISTINCT b.order_id), 0), 0) AS average_order_value`
+
+// reportQuery assembles the SQL and arguments for a grouping. An empty groupBy yields a single total row.
+func reportQuery(params reportParams, groupBy string) (string, []interface{}, error) {
+	var base, keys, joins, group, order string
+
+	switch groupBy {
+	case ReportByDay, ReportByWeek, ReportByMonth:
+		base = orderReportBase
+		keys = fmt.Sprintf("to_char(date_trunc('%s', b.local_date), 'YYYY-MM-DD') AS period,", groupBy)
+		group = "GROUP BY 1"
+		order = "ORDER BY 1"
+	case ReportByPaymentMethod:
+		base = orderReportBase
+		keys = "COALESCE(b.payment_method, '') AS payment_method,"
+		group = "GROUP BY 1"
+		order = "ORDER BY net_sales DESC"
+	case ReportByMenu:
+		base = lineReportBase
+		keys = "b.menu_id AS menu_id, COALESCE(m.name, '') AS menu_name,"
+		joins = "LEFT JOIN menus m ON m.id = b.menu_id"
+		group = "GROUP BY b.menu_id, m.name"
+		order = "ORDER BY net_sales DESC"
+	case ReportByCategory:
+		base = lineReportBase
+		keys = "COALESCE(m.category_id, 0) AS category_id, COALESCE(cat.category_name, '') AS category_name,"
+		joins = "LEFT JOIN menus m ON m.id = b.menu_id LEFT JOIN categories cat ON cat.id = m.category_id"
+		group = "GROUP BY m.category_id, cat.category_name"
+		order = "ORDER BY net_sales DESC"
+	case "":
+		base = orderReportBase
+	default:
+		return "", nil, i18n.Errorf("invalid group_by %q", groupBy)
+	}
+
+	query := fmt.Sprintf(`
+WITH scoped AS (
+	SELECT o.id, o.total_price, o.order_status,
+		COALESCE(o.discount, 0) AS discount,
+		COALESCE(o.tax, o.total_price * ?) AS tax,
+		(SELECT COALESCE(SUM(d.quantity), 0) FROM order_details d WHERE d.order_id = o.id) AS items,
+		(CAST(o.order_date AS timestamp) AT TIME ZONE 'UTC') AT TIME ZONE ? AS local_date
+	FROM orders o
+	WHERE o.order_status IN (?)
+), in_range AS (
+	SELECT * FROM scoped WHERE local_date >= ? AND local_date < ?
+), base AS (%s
+)
+SELECT %s %s
+FROM base b %s
+%s
+%s`, base, keys, reportFigures, joins, group, order)
+
+	args := []interface{}{
+		models.TaxRate,
+		params.Timezone,
+		reportOrderStatuses,
+		params.From.Format("2006-01-02 15:04:05"),
+		params.To.AddDate(0, 0, 1).Format("2006-01-02 15:04:05"),
+	}
+
+	return query, args, nil
+}
+
+// buildSalesReport aggregates paid orders for the given grouping entirely in SQL.
+func buildSalesReport(db *gorm.DB, params reportParams) (models.SalesReport, error) {
+	report := models.SalesReport{
+		From:     params.From.Format("2006-01-02"),
+		To:       params.To.Format("2006-01-02"),
+		Timezone: params.Timezone,
+		GroupBy:  params.GroupBy,
+		Rows:     []models.SalesReportRow{},
+	}
+
+	query, args, err := reportQuery(params, params.GroupBy)
+	if err != nil {
+		return report, err
+	}
+	if err := db.Raw(query, args...).Scan(&report.Rows).Error; err != nil {
+		return report, err
+	}
+
+	query, args, _ = reportQuery(params, "")
+	var total models.SalesReportRow
+	if err := db.Raw(query, args...).Scan(&total).Error; err != nil {
+		return report, err
+	}
+	report.Total = total.SalesFigures
+
+	return report, nil
+}
+
+func GetSalesReport(c *gin.Context, db *gorm.DB) {
+	groupBy := c.DefaultQuery("group_by", ReportByDay)
+	if groupBy != ReportByDay && groupBy != ReportByWeek && groupBy != ReportByMonth {
+		apperror.Abort(c, apperror.Invalid("group_by must be one of day, week or month"))
+		return
+	}
+
+	respondSalesReport(c, db, groupBy)
+}
+
+func GetMenuSalesReport(c *gin.Context, db *gorm.DB) {
+	respondSalesReport(c, db, ReportByMenu)
+}
+
+func GetCategorySalesReport(c *gin.Context, db *gorm.DB) {
+	respondSalesReport(c, db, ReportByCategory)
+}
+
+func GetPaymentMethodSalesReport(c *gin.Context, db *gorm.DB) {
+	respondSalesReport(c, db, ReportByPaymentMethod)
+}
+
+func respondSalesReport(c *gin.Context, db *gorm.DB, groupBy string) {
+	params, err := parseReportParams(c, groupBy)
+	if err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	report, err := buildSalesReport(db, params)
+	if err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to build sales report"))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
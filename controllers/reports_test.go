@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+func reportContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/reports/sales?"+query, nil)
+	return c
+}
+
+func TestParseReportParams(t *testing.T) {
+	params, err := parseReportParams(reportContext("from=2024-12-01&to=2024-12-31&tz=Europe/Berlin"), ReportByDay)
+	if err != nil {
+		t.Fatalf("parseReportParams: %v", err)
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	if !params.From.Equal(time.Date(2024, 12, 1, 0, 0, 0, 0, berlin)) || !params.To.Equal(time.Date(2024, 12, 31, 0, 0, 0, 0, berlin)) {
+		t.Errorf("got %s to %s, want December in Berlin", params.From, params.To)
+	}
+	if params.Timezone != "Europe/Berlin" || params.GroupBy != ReportByDay {
+		t.Errorf("got %+v", params)
+	}
+
+	// Without parameters the last 30 days in Jakarta are reported
+	params, err = parseReportParams(reportContext(""), "")
+	if err != nil {
+		t.Fatalf("parseReportParams: %v", err)
+	}
+	if params.Timezone != "Asia/Jakarta" || params.To.Sub(params.From) != 29*24*time.Hour {
+		t.Errorf("got %s from %s to %s, want 30 days in Asia/Jakarta", params.Timezone, params.From, params.To)
+	}
+	if params.To.Location().String() != "Asia/Jakarta" || params.To.Hour() != 0 {
+		t.Errorf("range must start at midnight in the report timezone, got %s", params.To)
+	}
+
+	for _, query := range []string{
+		"tz=Mars/Olympus",
+		"from=01-12-2024",
+		"to=2024-13-01",
+		"from=2024-12-31&to=2024-12-01",
+	} {
+		if _, err := parseReportParams(reportContext(query), ""); err == nil {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+}
+
+func TestReportQueryRange(t *testing.T) {
+	params, err := parseReportParams(reportContext("from=2024-12-01&to=2024-12-31&tz=Asia/Jakarta"), ReportByDay)
+	if err != nil {
+		t.Fatalf("parseReportParams: %v", err)
+	}
+	_, args, err := reportQuery(params, ReportByDay)
+	if err != nil {
+		t.Fatalf("reportQuery: %v", err)
+	}
+	// local_date is compared in the report timezone, the upper bound is exclusive
+	if args[1] != "Asia/Jakarta" || args[3] != "2024-12-01 00:00:00" || args[4] != "2025-01-01 00:00:00" {
+		t.Errorf("got args %v", args)
+	}
+
+	if _, _, err := reportQuery(params, "hour"); err == nil {
+		t.Errorf("expected unknown groupings to be rejected")
+	}
+}
+
+type reportLine struct {
+	OrderID  int
+	MenuID   int
+	Gross    float64
+	Discount float64
+	Refund   float64
+	Tax      float64
+}
+
+// reportLines runs base, one of the report bases, against an in_range table standing
+// in for the PostgreSQL-only CTEs of reportQuery.
+func reportLines(t *testing.T, db *gorm.DB, base string) []reportLine {
+	t.Helper()
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS in_range (id integer, local_date datetime, total_price real, order_status text, discount real, tax real, items integer)`).Error; err != nil {
+		t.Fatalf("create in_range: %v", err)
+	}
+	db.Exec(`DELETE FROM in_range`)
+	if err := db.Exec(`INSERT INTO in_range SELECT id, order_date, total_price, order_status, discount, tax, 0 FROM orders`).Error; err != nil {
+		t.Fatalf("fill in_range: %v", err)
+	}
+	columns := "b.order_id, b.gross, b.discount, b.refund, b.tax"
+	if base == lineReportBase {
+		columns += ", b.menu_id"
+	}
+	var rows []reportLine
+	if err := db.Raw(`SELECT ` + columns + ` FROM (` + base + `) b ORDER BY b.order_id`).Scan(&rows).Error; err != nil {
+		t.Fatalf("query report base: %v", err)
+	}
+	return rows
+}
+
+func TestLineReportDiscounts(t *testing.T) {
+	db := openTestDB(t)
+	// Only the carbonara line has a price rule, the order discount is its saving
+	db.Create(&models.Order{ID: 1, OrderDate: "2024-12-23 09:00:00", OrderStatus: models.OrderStatusCompleted, TotalPrice: 140000, Discount: 20000, Tax: 14000})
+	db.Create(&models.OrderDetail{OrderID: 1, MenuID: 1, Quantity: 2, UnitPrice: 40000, Discount: 20000, SubtotalPrice: 80000})
+	db.Create(&models.OrderDetail{OrderID: 1, MenuID: 2, Quantity: 1, UnitPrice: 60000, SubtotalPrice: 60000})
+	// Lines stored before line discounts were recorded share the order discount
+	db.Create(&models.Order{ID: 2, OrderDate: "2024-12-23 09:00:00", OrderStatus: models.OrderStatusCompleted, TotalPrice: 100000, Discount: 10000, Tax: 10000})
+	db.Exec(`INSERT INTO order_details (order_id, menu_id, quantity, subtotal_price) VALUES (2, 3, 1, 100000)`)
+
+	lines := map[int]reportLine{}
+	for _, line := range reportLines(t, db, lineReportBase) {
+		lines[line.MenuID] = line
+	}
+	if line := lines[1]; line.Gross != 100000 || line.Discount != 20000 {
+		t.Errorf("got discounted line %+v, want gross 100000 discount 20000", line)
+	}
+	if line := lines[2]; line.Gross != 60000 || line.Discount != 0 {
+		t.Errorf("got undiscounted line %+v, want gross 60000 without a discount", line)
+	}
+	if line := lines[3]; line.Gross != 110000 || line.Discount != 10000 {
+		t.Errorf("got legacy line %+v, want the order discount prorated", line)
+	}
+}
+
+func TestReportRefundedTax(t *testing.T) {
+	db := openTestDB(t)
+	db.Create(&models.Order{ID: 1, OrderDate: "2024-12-23 09:00:00", OrderStatus: models.OrderStatusCompleted, TotalPrice: 50000, Tax: 5000})
+	db.Create(&models.OrderDetail{OrderID: 1, MenuID: 1, Quantity: 1, SubtotalPrice: 50000})
+	db.Create(&models.Order{ID: 2, OrderDate: "2024-12-23 09:00:00", OrderStatus: "refunded", TotalPrice: 50000, Tax: 5000})
+	db.Create(&models.OrderDetail{OrderID: 2, MenuID: 1, Quantity: 1, SubtotalPrice: 50000})
+
+	for name, base := range map[string]string{"order": orderReportBase, "line": lineReportBase} {
+		rows := reportLines(t, db, base)
+		if len(rows) != 2 || rows[0].Tax != 5000 || rows[0].Refund != 0 {
+			t.Fatalf("%s: got %+v, want a taxed paid order", name, rows)
+		}
+		// The refund returns the tax with the sale, so none is reported
+		if rows[1].Refund != 50000 || rows[1].Tax != 0 {
+			t.Errorf("%s: got refunded %+v, want refund 50000 without tax", name, rows[1])
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+func CreateReview(c *gin.Context, db *gorm.DB) {
+	var request models.ReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	// The transaction code and email together authorize the customer
+	var order models.Order
+	err := db.Preload("Payment").
+		Joins("JOIN payments ON payments.order_id = orders.id").
+		Where("payments.transaction_code = ? AND LOWER(orders.email) = ?", request.TransactionCode, strings.ToLower(strings.TrimSpace(request.Email))).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperror.Abort(c, apperror.NotFound("Order not found"))
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "Failed to fetch order"))
+		}
+		return
+	}
+
+	if !models.IsPaidStatus(order.OrderStatus) {
+		apperror.Abort(c, apperror.Unprocessable("Only paid orders can be reviewed"))
+		return
+	}
+
+	var detail models.OrderDetail
+	if err := db.Where("id = ? AND order_id = ?", request.OrderDetailID, order.ID).First(&detail).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperror.Abort(c, apperror.NotFound("Order item not found"))
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "Failed to fetch order item"))
+		}
+		return
+	}
+
+	var existing int
+	if err := db.Model(&models.Review{}).Where("order_detail_id = ?", detail.ID).Count(&existing).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to check existing review"))
+		return
+	}
+	if existing > 0 {
+		apperror.Abort(c, apperror.Conflict("This item has already been reviewed"))
+		return
+	}
+
+	review := models.Review{
+		OrderID:       order.ID,
+		OrderDetailID: detail.ID,
+		MenuID:        detail.MenuID,
+		Name:          order.Name,
+		Rating:        request.Rating,
+		Comment:       strings.TrimSpace(request.Comment),
+		Status:        models.ReviewStatusPublished,
+		CreatedAt:     time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+
+	tx := db.Begin()
+	if err := tx.Create(&review).Error; err != nil {
+		tx.Rollback()
+		// A concurrent submission for the same line may pass the check above first
+		if isUniqueViolation(err) {
+			apperror.Abort(c, apperror.Conflict("This item has already been reviewed"))
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "Error creating review"))
+		}
+		return
+	}
+	if err := recomputeMenuRating(tx, review.MenuID); err != nil {
+		tx.Rollback()
+		apperror.Abort(c, apperror.Internal(err, "Error updating menu rating"))
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error creating review"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccessWithData{
+		Status:  "OK",
+		Message: tr(c, "Successfully submitted review"),
+		Code:    http.StatusOK,
+		Data:    review,
+	})
+}
+
+func GetMenuReviews(c *gin.Context, db *gorm.DB) {
+	menuID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid params id"))
+		return
+	}
+
+	limit, offset := paginationParams(c)
+
+	reviews := []models.PublicReview{}
+	if err := db.Model(&models.Review{}).
+		Select("id, menu_id, name, rating, comment, created_at").
+		Where("menu_id = ? AND status = ?", menuID, models.ReviewStatusPublished).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&reviews).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to fetch reviews"))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// GetReviews lists reviews of every status for moderation.
+func GetReviews(c *gin.Context, db *gorm.DB) {
+	query := db.Order("created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if menuID := c.Query("menu_id"); menuID != "" {
+		query = query.Where("menu_id = ?", menuID)
+	}
+
+	limit, offset := paginationParams(c)
+
+	var reviews []models.Review
+	if err := query.Limit(limit).Offset(offset).Find(&reviews).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Failed to fetch reviews"))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ModerateReview publishes or hides a review and refreshes the menu rating.
+func ModerateReview(c *gin.Context, db *gorm.DB) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid review ID"))
+		return
+	}
+
+	var request models.ReviewStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	var review models.Review
+	if err := db.First(&review, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperror.Abort(c, apperror.NotFound("Review not found"))
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "Failed to fetch review"))
+		}
+		return
+	}
+
+	tx := db.Begin()
+	if err := tx.Model(&review).Update("status", request.Status).Error; err != nil {
+		tx.Rollback()
+		apperror.Abort(c, apperror.Internal(err, "Error updating review"))
+		return
+	}
+	if err := recomputeMenuRating(tx, review.MenuID); err != nil {
+		tx.Rollback()
+		apperror.Abort(c, apperror.Internal(err, "Error updating menu rating"))
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error updating review"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully updated review"),
+		Code:    http.StatusOK,
+	})
+}
+
+func DeleteReview(c *gin.Context, db *gorm.DB) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid review ID"))
+		return
+	}
+
+	var review models.Review
+	if err := db.First(&review, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperror.Abort(c, apperror.NotFound("Review not found"))
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "Failed to fetch review"))
+		}
+		return
+	}
+
+	tx := db.Begin()
+	if err := tx.Delete(&review).Error; err != nil {
+		tx.Rollback()
+		apperror.Abort(c, apperror.Internal(err, "Error deleting review"))
+		return
+	}
+	if err := recomputeMenuRating(tx, review.MenuID); err != nil {
+		tx.Rollback()
+		apperror.Abort(c, apperror.Internal(err, "Error updating menu rating"))
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error deleting review"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully deleted review"),
+		Code:    http.StatusOK,
+	})
+}
+
+// recomputeMenuRating refreshes the cached average and count from published reviews.
+func recomputeMenuRating(db *gorm.DB, menuID int) error {
+	var aggregate struct {
+		Average float64
+		Count   int
+	}
+	if err := db.Model(&models.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("menu_id = ? AND status = ?", menuID, models.ReviewStatusPublished).
+		Scan(&aggregate).Error; err != nil {
+		return err
+	}
+
+	return db.Model(&models.Menu{}).Where("id = ?", menuID).Updates(map[string]interface{}{
+		"rating":         int(math.Round(aggregate.Average)),
+		"rating_average": math.Round(aggregate.Average*100) / 100,
+		"rating_count":   aggregate.Count,
+	}).Error
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation in PostgreSQL
+// or, for tests, SQLite.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// paginationParams reads limit (default 20, max 100) and offset from the query string.
+func paginationParams(c *gin.Context) (int, int) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
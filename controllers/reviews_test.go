@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+func TestCreateReview(t *testing.T) {
+	db := openTestDB(t)
+	db.Create(&models.Menu{ID: 1, Name: "Carbonara", Price: 50000})
+	orders := []struct {
+		id      int
+		status  string
+		code    string
+		details []int
+	}{
+		{1, "success", "TRX-PAID", []int{10, 11}},
+		{2, "pending", "TRX-PENDING", []int{20}},
+	}
+	for _, o := range orders {
+		db.Create(&models.Order{ID: o.id, Email: "Budi@Example.com", Name: "Budi", OrderStatus: o.status})
+		db.Create(&models.Payment{OrderID: o.id, TransactionCode: o.code, PaymentStatus: o.status})
+		for _, detail := range o.details {
+			db.Create(&models.OrderDetail{ID: detail, OrderID: o.id, MenuID: 1, Quantity: 1})
+		}
+	}
+
+	review := func(code, email string, detail, rating int) (int, apperror.Response) {
+		body := fmt.Sprintf(`{"transaction_code": %q, "email": %q, "order_detail_id": %d, "rating": %d}`, code, email, detail, rating)
+		w := serve(func(c *gin.Context) { CreateReview(c, db) }, http.MethodPost, "/reviews", "/reviews", body)
+		var response apperror.Response
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	rejections := []struct {
+		name   string
+		code   string
+		email  string
+		detail int
+		status int
+		want   apperror.Code
+	}{
+		{"wrong email", "TRX-PAID", "someone@example.com", 10, http.StatusNotFound, apperror.CodeNotFound},
+		{"unpaid order", "TRX-PENDING", "budi@example.com", 20, http.StatusUnprocessableEntity, apperror.CodeUnprocessable},
+		{"line of another order", "TRX-PAID", "budi@example.com", 20, http.StatusNotFound, apperror.CodeNotFound},
+	}
+	for _, tt := range rejections {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, response := review(tt.code, tt.email, tt.detail, 5); status != tt.status || response.Error.Code != tt.want {
+				t.Errorf("got %d %s, want %d %s", status, response.Error.Code, tt.status, tt.want)
+			}
+		})
+	}
+
+	// The email matches regardless of case and surrounding spaces
+	if status, response := review("TRX-PAID", " budi@example.com ", 10, 4); status != http.StatusOK {
+		t.Fatalf("got %d %+v, want the review accepted", status, response.Error)
+	}
+	if status, response := review("TRX-PAID", "budi@example.com", 10, 1); status != http.StatusConflict || response.Error.Code != apperror.CodeConflict {
+		t.Errorf("got %d %s for a second review of the line, want 409 conflict", status, response.Error.Code)
+	}
+	if status, _ := review("TRX-PAID", "budi@example.com", 11, 5); status != http.StatusOK {
+		t.Fatalf("got %d, want the other line reviewed", status)
+	}
+
+	var menu models.Menu
+	db.First(&menu, 1)
+	if menu.RatingAverage != 4.5 || menu.RatingCount != 2 || menu.Rating != 5 {
+		t.Errorf("got rating %d average %v count %d, want 5, 4.5 and 2", menu.Rating, menu.RatingAverage, menu.RatingCount)
+	}
+}
+
+func TestCreateReviewRace(t *testing.T) {
+	db := openTestDB(t)
+	db.Create(&models.Menu{ID: 1, Name: "Carbonara", Price: 50000})
+	db.Create(&models.Order{ID: 1, Email: "budi@example.com", Name: "Budi", OrderStatus: "success"})
+	db.Create(&models.Payment{OrderID: 1, TransactionCode: "TRX-PAID", PaymentStatus: "success"})
+	db.Create(&models.OrderDetail{ID: 10, OrderID: 1, MenuID: 1, Quantity: 1})
+
+	// Another submission for the line lands between the check and the insert
+	db.Callback().Create().Before("gorm:create").Register("concurrent_review", func(scope *gorm.Scope) {
+		if _, ok := scope.Value.(*models.Review); ok {
+			scope.NewDB().Exec(`INSERT INTO reviews (order_id, order_detail_id, menu_id, rating, status) VALUES (1, 10, 1, 5, 'published')`)
+		}
+	})
+
+	body := `{"transaction_code": "TRX-PAID", "email": "budi@example.com", "order_detail_id": 10, "rating": 4}`
+	w := serve(func(c *gin.Context) { CreateReview(c, db) }, http.MethodPost, "/reviews", "/reviews", body)
+	var response apperror.Response
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusConflict || response.Error.Code != apperror.CodeConflict {
+		t.Errorf("got %d %s, want 409 conflict", w.Code, response.Error.Code)
+	}
+}
+
+func TestGetMenuReviews(t *testing.T) {
+	db := openTestDB(t)
+	db.Create(&models.Review{OrderID: 1, OrderDetailID: 10, MenuID: 1, Name: "Budi", Rating: 5, Status: models.ReviewStatusPublished})
+	db.Create(&models.Review{OrderID: 2, OrderDetailID: 20, MenuID: 1, Name: "Siti", Rating: 1, Status: models.ReviewStatusHidden})
+
+	w := serve(func(c *gin.Context) { GetMenuReviews(c, db) }, http.MethodGet, "/menus/:id/reviews", "/menus/1/reviews", "")
+	var reviews []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &reviews); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("got %d %s", w.Code, w.Body.String())
+	}
+	if len(reviews) != 1 || reviews[0]["name"] != "Budi" {
+		t.Fatalf("got %v, want only the published review", reviews)
+	}
+	// Order IDs are for staff, customers must not learn them from the listing
+	for _, key := range []string{"order_id", "order_detail_id"} {
+		if _, ok := reviews[0][key]; ok {
+			t.Errorf("public review exposes %s", key)
+		}
+	}
+}
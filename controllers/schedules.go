@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// validateScheduleTarget ensures a schedule or price rule points at exactly one existing menu or category.
+func validateScheduleTarget(db *gorm.DB, menuID, categoryID int) error {
+	if (menuID == 0) == (categoryID == 0) {
+		return i18n.Errorf("exactly one of menu_id or category_id must be set")
+	}
+
+	var count int
+	if menuID != 0 {
+		if err := db.Model(&models.Menu{}).Where("id = ?", menuID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return i18n.Errorf("menu not found")
+		}
+		return nil
+	}
+
+	if err := db.Model(&models.Category{}).Where("id = ?", categoryID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return i18n.Errorf("category not found")
+	}
+	return nil
+}
+
+func GetSchedules(c *gin.Context, db *gorm.DB) {
+	var schedules []models.AvailabilitySchedule
+	if err := db.Order("id").Find(&schedules).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error fetching schedules"))
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+func CreateSchedule(c *gin.Context, db *gorm.DB) {
+	var schedule models.AvailabilitySchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	if err := validateScheduleTarget(db, schedule.MenuID, schedule.CategoryID); err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+	if err := helpers.ValidateScheduleWindow(schedule.ScheduleWindow); err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	schedule.ID = 0
+	if err := db.Create(&schedule).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error creating schedule"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccessWithData{
+		Status:  "OK",
+		Message: tr(c, "Successfully created schedule"),
+		Code:    http.StatusOK,
+		Data:    schedule,
+	})
+}
+
+func UpdateSchedule(c *gin.Context, db *gorm.DB) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid schedule ID"))
+		return
+	}
+
+	var existing models.AvailabilitySchedule
+	if err := db.First(&existing, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperror.Abort(c, apperror.NotFound("Schedule not found"))
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "Error fetching schedule"))
+		}
+		return
+	}
+
+	var schedule models.AvailabilitySchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	if err := validateScheduleTarget(db, schedule.MenuID, schedule.CategoryID); err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+	if err := helpers.ValidateScheduleWindow(schedule.ScheduleWindow); err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	// Save every column so clearing a window bound (empty string) is persisted
+	schedule.ID = existing.ID
+	if err := db.Save(&schedule).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error updating schedule"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully updated schedule"),
+		Code:    http.StatusOK,
+	})
+}
+
+func DeleteSchedule(c *gin.Context, db *gorm.DB) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid schedule ID"))
+		return
+	}
+
+	result := db.Where("id = ?", id).Delete(&models.AvailabilitySchedule{})
+	if result.Error != nil {
+		apperror.Abort(c, apperror.Internal(result.Error, "Error deleting schedule"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apperror.Abort(c, apperror.NotFound("Schedule not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully deleted schedule"),
+		Code:    http.StatusOK,
+	})
+}
+
+func GetPriceRules(c *gin.Context, db *gorm.DB) {
+	var rules []models.PriceRule
+	if err := db.Order("id").Find(&rules).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error fetching price rules"))
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func CreatePriceRule(c *gin.Context, db *gorm.DB) {
+	var rule models.PriceRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	if err := validatePriceRule(db, rule); err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	rule.ID = 0
+	if err := db.Create(&rule).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error creating price rule"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccessWithData{
+		Status:  "OK",
+		Message: tr(c, "Successfully created price rule"),
+		Code:    http.StatusOK,
+		Data:    rule,
+	})
+}
+
+func UpdatePriceRule(c *gin.Context, db *gorm.DB) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid price rule ID"))
+		return
+	}
+
+	var existing models.PriceRule
+	if err := db.First(&existing, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperror.Abort(c, apperror.NotFound("Price rule not found"))
+		} else {
+			apperror.Abort(c, apperror.Internal(err, "Error fetching price rule"))
+		}
+		return
+	}
+
+	var rule models.PriceRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		apperror.Abort(c, apperror.Validation(err))
+		return
+	}
+
+	if err := validatePriceRule(db, rule); err != nil {
+		apperror.Abort(c, err)
+		return
+	}
+
+	rule.ID = existing.ID
+	if err := db.Save(&rule).Error; err != nil {
+		apperror.Abort(c, apperror.Internal(err, "Error updating price rule"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully updated price rule"),
+		Code:    http.StatusOK,
+	})
+}
+
+func DeletePriceRule(c *gin.Context, db *gorm.DB) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperror.Abort(c, apperror.Invalid("Invalid price rule ID"))
+		return
+	}
+
+	result := db.Where("id = ?", id).Delete(&models.PriceRule{})
+	if result.Error != nil {
+		apperror.Abort(c, apperror.Internal(result.Error, "Error deleting price rule"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apperror.Abort(c, apperror.NotFound("Price rule not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Status:  "OK",
+		Message: tr(c, "Successfully deleted price rule"),
+		Code:    http.StatusOK,
+	})
+}
+
+func validatePriceRule(db *gorm.DB, rule models.PriceRule) error {
+	if err := validateScheduleTarget(db, rule.MenuID, rule.CategoryID); err != nil {
+		return err
+	}
+	if err := helpers.ValidateScheduleWindow(rule.ScheduleWindow); err != nil {
+		return err
+	}
+	if rule.FixedPrice < 0 {
+		return i18n.Errorf("fixed_price must not be negative")
+	}
+	if rule.DiscountPercent < 0 || rule.DiscountPercent > 100 {
+		return i18n.Errorf("discount_percent must be between 0 and 100")
+	}
+	if rule.FixedPrice == 0 && rule.DiscountPercent == 0 {
+		return i18n.Errorf("either fixed_price or discount_percent must be set")
+	}
+	return nil
+}
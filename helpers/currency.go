@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"math"
+	"strconv"
+)
+
+// FormatRupiah formats an amount the Indonesian way, e.g. 1250000 -> "Rp1.250.000".
+// Rupiah has no minor unit in practice, so amounts are rounded to whole rupiah.
+func FormatRupiah(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(int64(math.Round(amount)), 10)
+
+	var grouped []byte
+	for i, d := range []byte(digits) {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped = append(grouped, '.')
+		}
+		grouped = append(grouped, d)
+	}
+
+	return sign + "Rp" + string(grouped)
+}
@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+var testCompany = utils.Company{Name: "Pesta Pasta", Email: "support@pestapasta.com", Phone: "123-456-7890"}
+
+func sampleOrder(method string) models.Order {
+	order := models.Order{
+		ID:          42,
+		OrderDate:   "2024-12-21 19:32:15",
+		Name:        "Dimas Febriyanto",
+		Email:       "dimas@example.com",
+		TotalPrice:  175000,
+		Discount:    25000,
+		Tax:         17500,
+		OrderStatus: "pending",
+		OrderDetails: []models.OrderDetail{
+			{Quantity: 3, UnitPrice: 50000, Discount: 0, SubtotalPrice: 150000, Notes: "Extra parmesan", Menu: models.Menu{Name: "Carbonara"}},
+			{Quantity: 1, UnitPrice: 25000, Discount: 25000, SubtotalPrice: 25000, Menu: models.Menu{Name: "Aglio Olio"}},
+		},
+		Payment: models.Payment{
+			TransactionCode: "TXN42",
+			PaymentStatus:   "pending",
+		},
+	}
+
+	if method == "qris" {
+		order.Payment.PaymentMethod = "QRIS"
+		order.Payment.PaymentQRCodeURL = "https://api.midtrans.com/v2/qris/TXN42/qr-code"
+	} else {
+		order.Payment.PaymentMethod = "BCA"
+		order.Payment.PaymentAccountName = "Pesta Pasta"
+		order.Payment.PaymentAccountNumber = "1234567890"
+	}
+	return order
+}
+
+func TestConvertOrderToInvoiceDataUsesStoredValues(t *testing.T) {
+	data := ConvertOrderToInvoiceData(sampleOrder("bca"), testCompany)
+
+	if data.Items[0].Quantity != 3 || data.Items[0].TotalPrice != 150000 || data.Items[0].Notes != "Extra parmesan" {
+		t.Errorf("first line not taken from order detail: %+v", data.Items[0])
+	}
+	if len(data.Items[1].Modifiers) != 1 {
+		t.Errorf("discounted line should carry a modifier: %+v", data.Items[1])
+	}
+	if data.Subtotal != 175000 || data.Tax != 17500 || data.Total != 192500 {
+		t.Errorf("totals not taken from order: subtotal=%v tax=%v total=%v", data.Subtotal, data.Tax, data.Total)
+	}
+	if data.Date != "2024-12-21" || data.PaymentMethod != "bca" {
+		t.Errorf("unexpected date %q or payment method %q", data.Date, data.PaymentMethod)
+	}
+}
+
+func TestInvoiceGolden(t *testing.T) {
+	for _, lang := range []string{i18n.Indonesian, i18n.English} {
+		for _, method := range []string{"qris", "bca"} {
+			order := sampleOrder(method)
+			order.Language = lang
+
+			rendered, err := RenderEmail(TemplateInvoice, ConvertOrderToInvoiceData(order, testCompany))
+			if err != nil {
+				t.Fatalf("%s/%s: %v", lang, method, err)
+			}
+
+			name := "invoice_" + method + "_" + lang
+			assertGolden(t, name+".html", rendered.HTML)
+			assertGolden(t, name+".txt", rendered.Text)
+		}
+	}
+}
+
+// assertGolden compares got with testdata/name. Run `go test ./helpers -update` to accept changes.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file %s, run with -update to create it: %v", path, err)
+	}
+	if string(want) != got {
+		t.Errorf("%s does not match rendered output, run with -update if the change is intended\n--- got ---\n%s", path, got)
+	}
+}
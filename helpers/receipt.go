@@ -0,0 +1,156 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// RenderReceiptPDF renders a printable version of the invoice email.
+func RenderReceiptPDF(data InvoiceData) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(i18n.T(i18n.Resolve(data.Language), "Invoice")+" "+data.InvoiceNumber, true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.AddPage()
+
+	// Core fonts are cp1252; translate so customer names with accents still render
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	t := func(message string, args ...interface{}) string {
+		return tr(i18n.T(i18n.Resolve(data.Language), message, args...))
+	}
+
+	pdf.SetFont("Helvetica", "B", 22)
+	pdf.SetTextColor(0, 123, 255)
+	pdf.CellFormat(0, 10, t("INVOICE"), "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(51, 51, 51)
+	pdf.CellFormat(0, 5, t("Invoice Number")+": "+data.InvoiceNumber, "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, t("Invoice Date")+": "+data.Date, "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, t("Payment Status")+": "+strings.ToUpper(t(data.PaymentStatus)), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(90, 6, t("FROM")+":", "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 6, t("BILL TO")+":", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(90, 5, tr(data.CompanyName), "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr(data.ClientName), "", 1, "L", false, 0, "")
+	pdf.CellFormat(90, 5, data.CompanyEmail, "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr(data.ClientEmail), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	// Itemized lines
+	widths := []float64{80, 20, 40, 40}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(0, 123, 255)
+	pdf.SetTextColor(255, 255, 255)
+	for i, title := range []string{t("Product"), t("Quantity"), t("Unit Price"), t("Total")} {
+		pdf.CellFormat(widths[i], 8, title, "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(51, 51, 51)
+	for _, item := range data.Items {
+		name := item.ProductName
+		for _, modifier := range item.Modifiers {
+			name += ", " + modifier
+		}
+		if item.Notes != "" {
+			name += " (" + item.Notes + ")"
+		}
+
+		pdf.CellFormat(widths[0], 7, tr(name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, fmt.Sprint(item.Quantity), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 7, FormatRupiah(item.UnitPrice), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 7, FormatRupiah(item.TotalPrice), "1", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 10)
+	totals := [][2]string{{t("Subtotal"), FormatRupiah(data.Subtotal)}}
+	if data.Discount > 0 {
+		totals = append(totals, [2]string{t("Discount (included above)"), FormatRupiah(data.Discount)})
+	}
+	totals = append(totals, [2]string{t("Tax"), FormatRupiah(data.Tax)})
+	for _, line := range totals {
+		pdf.CellFormat(140, 6, line[0]+":", "", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 6, line[1], "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.SetTextColor(0, 123, 255)
+	pdf.CellFormat(140, 9, t("Total")+":", "", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 9, FormatRupiah(data.Total), "", 1, "R", false, 0, "")
+	pdf.Ln(4)
+
+	// Payment information
+	pdf.SetTextColor(51, 51, 51)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 7, t("Payment Information"), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+
+	if data.PaymentMethod == "qris" {
+		pdf.CellFormat(0, 5, t("QRIS - scan the QR code to complete your payment."), "", 1, "L", false, 0, "")
+		if png, err := receiptQRCode(data); err == nil && registerQRCode(pdf, png) {
+			pdf.ImageOptions("qris", 15, pdf.GetY()+2, 40, 40, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+			pdf.SetY(pdf.GetY() + 44)
+		} else {
+			pdf.CellFormat(0, 5, t("QR code unavailable, open: %s", data.PaymentQRCodeURL), "", 1, "L", false, 0, "")
+		}
+	} else {
+		pdf.CellFormat(0, 5, t("Bank Transfer")+" - "+strings.ToUpper(data.PaymentMethod), "", 1, "L", false, 0, "")
+		pdf.CellFormat(0, 5, t("Account Name")+": "+tr(data.PaymentAccountName), "", 1, "L", false, 0, "")
+		pdf.CellFormat(0, 5, t("Account Number")+": "+data.PaymentAccountNumber, "", 1, "L", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render receipt: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// registerQRCode adds png to pdf as the "qris" image. An image fpdf cannot read would
+// fail the whole document, so the error is cleared and false returned instead.
+func registerQRCode(pdf *fpdf.Fpdf, png []byte) bool {
+	pdf.RegisterImageOptionsReader("qris", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	if pdf.Err() {
+		pdf.ClearError()
+		return false
+	}
+	return true
+}
+
+// receiptQRCode encodes the stored QRIS payload, falling back to downloading
+// the gateway's QR image for payments created before the payload was stored.
+func receiptQRCode(data InvoiceData) ([]byte, error) {
+	if data.PaymentQRString != "" {
+		return qrcode.Encode(data.PaymentQRString, qrcode.Medium, 256)
+	}
+	if data.PaymentQRCodeURL == "" {
+		return nil, fmt.Errorf("payment has no QR code")
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(data.PaymentQRCodeURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download QR code, status: %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/png") {
+		return nil, fmt.Errorf("QR code is %q, not a PNG", contentType)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenderReceiptPDF(t *testing.T) {
+	// Any image served back counts as the gateway's QR code
+	png, err := receiptQRCode(InvoiceData{PaymentQRString: "00020101021226"})
+	if err != nil {
+		t.Fatalf("encode QR: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/qr.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+		case "/error":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>Service Unavailable</html>"))
+		case "/corrupt":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\xff\xd8\xff\xe0 not a png"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	qris := func(configure func(*InvoiceData)) InvoiceData {
+		data := ConvertOrderToInvoiceData(sampleOrder("qris"), testCompany)
+		configure(&data)
+		return data
+	}
+	tests := []struct {
+		name      string
+		data      InvoiceData
+		wantImage bool
+	}{
+		{"bank transfer", ConvertOrderToInvoiceData(sampleOrder("bca"), testCompany), false},
+		{"stored QR payload", qris(func(d *InvoiceData) { d.PaymentQRString = "00020101021226" }), true},
+		{"downloaded QR image", qris(func(d *InvoiceData) { d.PaymentQRCodeURL = server.URL + "/qr.png" }), true},
+		{"gateway error page", qris(func(d *InvoiceData) { d.PaymentQRCodeURL = server.URL + "/error" }), false},
+		{"image fpdf cannot read", qris(func(d *InvoiceData) { d.PaymentQRCodeURL = server.URL + "/corrupt" }), false},
+		{"missing image", qris(func(d *InvoiceData) { d.PaymentQRCodeURL = server.URL + "/gone" }), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf, err := RenderReceiptPDF(tt.data)
+			if err != nil {
+				t.Fatalf("RenderReceiptPDF: %v", err)
+			}
+			if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+				t.Fatalf("output is not a PDF")
+			}
+			if hasImage := bytes.Contains(pdf, []byte("/Subtype /Image")); hasImage != tt.wantImage {
+				t.Errorf("got QR image %v, want %v", hasImage, tt.wantImage)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+)
+
+// Jakarta is the timezone every schedule and price rule is evaluated in.
+var Jakarta = loadJakarta()
+
+func loadJakarta() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		// Slim images may ship without tzdata; WIB has no DST so a fixed zone is equivalent
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// ValidateScheduleWindow checks that every field of the window is well formed.
+func ValidateScheduleWindow(w models.ScheduleWindow) error {
+	if _, err := parseDays(w.DaysOfWeek); err != nil {
+		return err
+	}
+	for _, t := range []string{w.StartTime, w.EndTime} {
+		if t == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", t); err != nil {
+			return i18n.Errorf("invalid time %q, expected HH:MM", t)
+		}
+	}
+	for _, d := range []string{w.StartDate, w.EndDate} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return i18n.Errorf("invalid date %q, expected YYYY-MM-DD", d)
+		}
+	}
+	if w.StartDate != "" && w.EndDate != "" && w.EndDate < w.StartDate {
+		return i18n.Errorf("end_date must not be before start_date")
+	}
+	return nil
+}
+
+// WindowContains reports whether t, converted to Asia/Jakarta, falls inside the window.
+func WindowContains(w models.ScheduleWindow, t time.Time) bool {
+	t = t.In(Jakarta)
+
+	date := t.Format("2006-01-02")
+	if w.StartDate != "" && date < w.StartDate {
+		return false
+	}
+	if w.EndDate != "" && date > w.EndDate {
+		return false
+	}
+
+	days, err := parseDays(w.DaysOfWeek)
+	if err != nil {
+		return false
+	}
+	if len(days) > 0 && !days[t.Weekday()] {
+		return false
+	}
+
+	clock := t.Format("15:04")
+	start, end := w.StartTime, w.EndTime
+	switch {
+	case start == "" && end == "":
+		return true
+	case start == "":
+		return clock < end
+	case end == "":
+		return clock >= start
+	case start <= end:
+		return clock >= start && clock < end
+	default:
+		// Window crosses midnight, e.g. 22:00-02:00
+		return clock >= start || clock < end
+	}
+}
+
+// ApplySchedules fills AvailableNow and EffectivePrice on every menu for the moment now.
+// A menu is available when it is toggled on and, for each of its own and its category's
+// schedules (if any exist), at least one window matches. The lowest matching price rule wins.
+func ApplySchedules(menus []models.Menu, schedules []models.AvailabilitySchedule, rules []models.PriceRule, now time.Time) {
+	for i := range menus {
+		menu := &menus[i]
+		menu.AvailableNow = menu.IsAvailable && isScheduled(*menu, schedules, now)
+		menu.EffectivePrice = effectivePrice(*menu, rules, now)
+	}
+}
+
+func isScheduled(menu models.Menu, schedules []models.AvailabilitySchedule, now time.Time) bool {
+	var hasMenu, hasCategory, matchMenu, matchCategory bool
+	for _, s := range schedules {
+		switch {
+		case s.MenuID != 0 && s.MenuID == menu.ID:
+			hasMenu = true
+			matchMenu = matchMenu || WindowContains(s.ScheduleWindow, now)
+		case s.MenuID == 0 && s.CategoryID != 0 && s.CategoryID == menu.CategoryID:
+			hasCategory = true
+			matchCategory = matchCategory || WindowContains(s.ScheduleWindow, now)
+		}
+	}
+	return (!hasMenu || matchMenu) && (!hasCategory || matchCategory)
+}
+
+func effectivePrice(menu models.Menu, rules []models.PriceRule, now time.Time) float64 {
+	price := menu.Price
+	for _, r := range rules {
+		appliesToMenu := r.MenuID != 0 && r.MenuID == menu.ID
+		appliesToCategory := r.MenuID == 0 && r.CategoryID != 0 && r.CategoryID == menu.CategoryID
+		if !appliesToMenu && !appliesToCategory {
+			continue
+		}
+		if !WindowContains(r.ScheduleWindow, now) {
+			continue
+		}
+
+		candidate := menu.Price
+		if r.FixedPrice > 0 {
+			candidate = r.FixedPrice
+		} else if r.DiscountPercent > 0 {
+			candidate = menu.Price * (1 - r.DiscountPercent/100)
+		}
+		if candidate < price {
+			price = candidate
+		}
+	}
+	return price
+}
+
+func parseDays(s string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	if strings.TrimSpace(s) == "" {
+		return days, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || n > 6 {
+			return nil, i18n.Errorf("invalid day of week %q, expected 0 (Sunday) to 6 (Saturday)", part)
+		}
+		days[time.Weekday(n)] = true
+	}
+	return days, nil
+}
@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/models"
+)
+
+func TestApplySchedules(t *testing.T) {
+	menus := []models.Menu{
+		{ID: 1, Name: "Breakfast Carbonara", Price: 50000, CategoryID: 1, IsAvailable: true},
+		{ID: 2, Name: "Aglio Olio", Price: 40000, CategoryID: 2, IsAvailable: true},
+		{ID: 3, Name: "Lasagna", Price: 60000, CategoryID: 2, IsAvailable: false},
+	}
+	schedules := []models.AvailabilitySchedule{
+		{MenuID: 1, ScheduleWindow: models.ScheduleWindow{StartTime: "06:00", EndTime: "11:00"}},
+	}
+	rules := []models.PriceRule{
+		{CategoryID: 2, DiscountPercent: 25, ScheduleWindow: models.ScheduleWindow{DaysOfWeek: "1,2,3,4,5", StartTime: "15:00", EndTime: "17:00"}},
+	}
+
+	// Monday 2024-12-23 16:00 WIB is 09:00 UTC
+	now := time.Date(2024, 12, 23, 9, 0, 0, 0, time.UTC)
+	ApplySchedules(menus, schedules, rules, now)
+
+	if menus[0].AvailableNow {
+		t.Errorf("breakfast item should not be available at 16:00 WIB")
+	}
+	if !menus[1].AvailableNow || menus[1].EffectivePrice != 30000 {
+		t.Errorf("happy hour not applied: available=%v price=%v", menus[1].AvailableNow, menus[1].EffectivePrice)
+	}
+	if menus[2].AvailableNow {
+		t.Errorf("manually disabled item must stay unavailable")
+	}
+
+	// Saturday morning: breakfast open, no happy hour
+	now = time.Date(2024, 12, 28, 1, 30, 0, 0, time.UTC)
+	ApplySchedules(menus, schedules, rules, now)
+
+	if !menus[0].AvailableNow {
+		t.Errorf("breakfast item should be available at 08:30 WIB")
+	}
+	if menus[1].EffectivePrice != 40000 {
+		t.Errorf("happy hour applied outside its window: %v", menus[1].EffectivePrice)
+	}
+}
+
+func TestWindowContainsCrossesMidnight(t *testing.T) {
+	w := models.ScheduleWindow{StartTime: "22:00", EndTime: "02:00", StartDate: "2024-12-01", EndDate: "2024-12-31"}
+
+	cases := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2024, 12, 10, 23, 0, 0, 0, Jakarta), true},
+		{time.Date(2024, 12, 10, 1, 0, 0, 0, Jakarta), true},
+		{time.Date(2024, 12, 10, 12, 0, 0, 0, Jakarta), false},
+		{time.Date(2025, 1, 1, 23, 0, 0, 0, Jakarta), false},
+	}
+	for _, tc := range cases {
+		if got := WindowContains(w, tc.at); got != tc.want {
+			t.Errorf("WindowContains(%v) = %v, want %v", tc.at, got, tc.want)
+		}
+	}
+}
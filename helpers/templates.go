@@ -0,0 +1,265 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+)
+
+// Email template names, one per order lifecycle event.
+const (
+	TemplateInvoice         = "invoice"
+	TemplatePaymentReceived = "payment_received"
+	TemplatePaymentExpired  = "payment_expired"
+	TemplateReadyForPickup  = "ready_for_pickup"
+	TemplateOrderCancelled  = "order_cancelled"
+	TemplateRefundIssued    = "refund_issued"
+)
+
+// RenderedEmail is a template rendered for one recipient.
+type RenderedEmail struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// TemplateSet holds the template sources of one email. Text is optional.
+type TemplateSet struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+// templateFuncs returns the functions available to email templates. Templates
+// write English text as {{t "Message"}}, translated into lang when rendered.
+func templateFuncs(lang string) map[string]interface{} {
+	return map[string]interface{}{
+		"rupiah": FormatRupiah,
+		"t": func(message string, args ...interface{}) string {
+			return i18n.T(lang, message, args...)
+		},
+	}
+}
+
+// RenderEmail renders the subject, HTML and plain-text parts of a built-in template
+// in data.Language.
+func RenderEmail(name string, data InvoiceData) (RenderedEmail, error) {
+	set, ok := builtinTemplates[name]
+	if !ok {
+		return RenderedEmail{}, fmt.Errorf("unknown email template %q", name)
+	}
+	return RenderTemplateSet(name, set, data)
+}
+
+// BuiltinTemplate returns the template compiled into the binary, used when no edited version is stored.
+func BuiltinTemplate(name string) (TemplateSet, bool) {
+	set, ok := builtinTemplates[name]
+	return set, ok
+}
+
+// TemplateNames lists the built-in template names in alphabetical order.
+func TemplateNames() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateTemplateSet parses every part of set and renders it against sample orders paid by
+// QRIS and by bank transfer, so unknown fields in either payment branch are rejected too.
+func ValidateTemplateSet(name string, set TemplateSet, lang string) error {
+	for _, method := range []string{"qris", "bca"} {
+		if _, err := RenderTemplateSet(name, set, SampleInvoiceData(lang, method)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SampleInvoiceData is a made-up order for previewing and validating templates.
+func SampleInvoiceData(lang, paymentMethod string) InvoiceData {
+	data := InvoiceData{
+		Language:      i18n.Resolve(lang),
+		Date:          time.Now().In(Jakarta).Format("2006-01-02"),
+		InvoiceNumber: "TXN12345",
+		CompanyName:   "Pesta Pasta",
+		CompanyEmail:  "support@pestapasta.com",
+		CompanyPhone:  "123-456-7890",
+		ClientName:    "Budi Santoso",
+		ClientEmail:   "budi@example.com",
+		Items: []InvoiceItem{
+			{ProductName: "Spaghetti Carbonara", Quantity: 2, UnitPrice: 55000, TotalPrice: 110000, Notes: "Extra cheese"},
+			{ProductName: "Iced Lemon Tea", Quantity: 1, UnitPrice: 15000, TotalPrice: 12000, Modifiers: []string{"Promo -Rp3.000"}},
+		},
+		Subtotal:      122000,
+		Discount:      3000,
+		Tax:           12200,
+		Total:         134200,
+		PaymentMethod: paymentMethod,
+		PaymentStatus: "pending",
+	}
+	if paymentMethod == "qris" {
+		data.PaymentQRCodeURL = "https://api.sandbox.midtrans.com/v2/qris/sample/qr-code"
+	} else {
+		data.PaymentAccountName = "Pesta Pasta"
+		data.PaymentAccountNumber = "1234567890"
+	}
+	return data
+}
+
+// RenderTemplateSet renders set with data, for both built-in and stored templates.
+func RenderTemplateSet(name string, set TemplateSet, data InvoiceData) (RenderedEmail, error) {
+	var rendered RenderedEmail
+	data.Language = i18n.Resolve(data.Language)
+
+	subject, err := executeText(name+"-subject", set.Subject, data)
+	if err != nil {
+		return rendered, err
+	}
+	rendered.Subject = subject
+
+	htmlTmpl, err := htmltemplate.New(name).Funcs(templateFuncs(data.Language)).Parse(set.HTML)
+	if err != nil {
+		return rendered, fmt.Errorf("failed to parse %s HTML template: %w", name, err)
+	}
+	var html bytes.Buffer
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return rendered, fmt.Errorf("failed to execute %s HTML template: %w", name, err)
+	}
+	rendered.HTML = html.String()
+
+	if rendered.Text, err = executeText(name+"-text", set.Text, data); err != nil {
+		return rendered, err
+	}
+	return rendered, nil
+}
+
+func executeText(name, text string, data InvoiceData) (string, error) {
+	tmpl, err := texttemplate.New(name).Funcs(templateFuncs(data.Language)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to execute %s template: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// lifecycleHTML wraps a status message with the order summary every lifecycle email shows.
+// Title and message are English catalog keys, translated when the template is rendered.
+func lifecycleHTML(title, message string) string {
+	return `<!DOCTYPE html>
+<html lang="{{.Language}}">
+  <head>
+    <meta charset="UTF-8" />
+    <title>{{t "` + title + `"}}</title>
+  </head>
+  <body style="font-family: Arial, sans-serif; line-height: 1.6; max-width: 800px; margin: 0 auto; padding: 20px; background-color: #f4f4f4; color: #333;">
+    <div style="border-bottom: 2px solid #007bff; padding-bottom: 15px; margin-bottom: 20px; color: #007bff;">
+      <h1 style="margin: 0; font-size: 2rem;">{{t "` + title + `"}}</h1>
+      <p style="margin: 0;">{{t "Order Number"}}: {{.InvoiceNumber}}</p>
+    </div>
+
+    <div style="background: #ffffff; padding: 20px; border-radius: 8px; box-shadow: 0 2px 5px rgba(0, 0, 0, 0.1);">
+      <p style="margin: 0 0 10px;">{{t "Hi %s," .ClientName}}</p>
+      <p style="margin: 0 0 20px;">{{t "` + message + `"}}</p>
+
+      <table style="width: 100%; border-collapse: collapse; margin: 20px 0;">
+        <tbody>
+          {{range .Items}}
+          <tr>
+            <td style="border: 1px solid #ddd; padding: 8px;">{{.Quantity}}x {{.ProductName}}</td>
+            <td style="border: 1px solid #ddd; padding: 8px; text-align: right;">{{rupiah .TotalPrice}}</td>
+          </tr>
+          {{end}}
+        </tbody>
+      </table>
+
+      <p style="text-align: right; margin: 0; font-weight: bold;">{{t "Total"}}: {{rupiah .Total}}</p>
+    </div>
+
+    <p style="margin-top: 20px; font-size: 0.9rem;">{{.CompanyName}} &middot; {{.CompanyEmail}} &middot; {{.CompanyPhone}}</p>
+  </body>
+</html>
+`
+}
+
+// lifecycleText is the plain-text counterpart of lifecycleHTML.
+func lifecycleText(title, message string) string {
+	return `{{t "` + title + `"}}
+{{t "Order Number"}}: {{.InvoiceNumber}}
+
+{{t "Hi %s," .ClientName}}
+
+{{t "` + message + `"}}
+
+{{range .Items}}{{.Quantity}}x {{.ProductName}}: {{rupiah .TotalPrice}}
+{{end}}
+{{t "Total"}}: {{rupiah .Total}}
+
+{{.CompanyName}} - {{.CompanyEmail}} - {{.CompanyPhone}}
+`
+}
+
+const invoiceText = `{{t "INVOICE"}}
+{{t "Invoice Date"}}: {{.Date}}
+{{t "Invoice Number"}}: {{.InvoiceNumber}}
+
+{{t "BILL TO"}}:
+{{.ClientName}}
+{{.ClientEmail}}
+
+{{range .Items}}{{.Quantity}}x {{.ProductName}} @ {{rupiah .UnitPrice}}: {{rupiah .TotalPrice}}
+{{range .Modifiers}}    {{.}}
+{{end}}{{if .Notes}}    {{t "Note"}}: {{.Notes}}
+{{end}}{{end}}
+{{t "Subtotal"}}: {{rupiah .Subtotal}}
+{{if .Discount}}{{t "Discount (included above)"}}: {{rupiah .Discount}}
+{{end}}{{t "Tax"}}: {{rupiah .Tax}}
+{{t "Total"}}: {{rupiah .Total}}
+
+{{if eq .PaymentMethod "qris"}}{{t "Scan the QR code to complete your payment: %s" .PaymentQRCodeURL}}{{else}}{{t "Transfer to %s (%s)." .PaymentAccountName .PaymentAccountNumber}}{{end}}
+
+{{.CompanyName}} - {{.CompanyEmail}} - {{.CompanyPhone}}
+`
+
+var builtinTemplates = map[string]TemplateSet{
+	TemplateInvoice: {
+		Subject: `{{t "Invoice for Your Purchase"}}`,
+		HTML:    emailTemplate,
+		Text:    invoiceText,
+	},
+	TemplatePaymentReceived: {
+		Subject: `{{t "Payment received for order %s" .InvoiceNumber}}`,
+		HTML:    lifecycleHTML("Payment Received", "We have received your payment. Your order is now being prepared."),
+		Text:    lifecycleText("Payment Received", "We have received your payment. Your order is now being prepared."),
+	},
+	TemplatePaymentExpired: {
+		Subject: `{{t "Payment for order %s has expired" .InvoiceNumber}}`,
+		HTML:    lifecycleHTML("Payment Expired", "We did not receive your payment in time, so this order has been closed. Feel free to place a new order."),
+		Text:    lifecycleText("Payment Expired", "We did not receive your payment in time, so this order has been closed. Feel free to place a new order."),
+	},
+	TemplateReadyForPickup: {
+		Subject: `{{t "Order %s is ready for pickup" .InvoiceNumber}}`,
+		HTML:    lifecycleHTML("Ready for Pickup", "Good news, your order is ready! Show this order number at the counter to pick it up."),
+		Text:    lifecycleText("Ready for Pickup", "Good news, your order is ready! Show this order number at the counter to pick it up."),
+	},
+	TemplateOrderCancelled: {
+		Subject: `{{t "Order %s has been cancelled" .InvoiceNumber}}`,
+		HTML:    lifecycleHTML("Order Cancelled", "Your order has been cancelled. If you did not expect this, please contact us."),
+		Text:    lifecycleText("Order Cancelled", "Your order has been cancelled. If you did not expect this, please contact us."),
+	},
+	TemplateRefundIssued: {
+		Subject: `{{t "Refund issued for order %s" .InvoiceNumber}}`,
+		HTML:    lifecycleHTML("Refund Issued", "We have issued a refund for this order. It may take a few business days to appear in your account."),
+		Text:    lifecycleText("Refund Issued", "We have issued a refund for this order. It may take a few business days to appear in your account."),
+	},
+}
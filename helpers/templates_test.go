@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderEmailBuiltinTemplates(t *testing.T) {
+	data := InvoiceData{
+		InvoiceNumber: "TXN42",
+		ClientName:    "Dimas",
+		Items:         []InvoiceItem{{ProductName: "Carbonara", Quantity: 3, UnitPrice: 50000, TotalPrice: 150000}},
+		Total:         165000,
+	}
+
+	for name := range builtinTemplates {
+		rendered, err := RenderEmail(name, data)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !strings.Contains(rendered.Subject+rendered.HTML, "TXN42") {
+			t.Errorf("%s: order number missing from subject and HTML", name)
+		}
+		if !strings.Contains(rendered.Text, "Carbonara") || !strings.Contains(rendered.HTML, "Carbonara") {
+			t.Errorf("%s: items missing from HTML or text part", name)
+		}
+	}
+}
+
+func TestValidateTemplateSet(t *testing.T) {
+	valid := TemplateSet{Subject: "Faktur {{.InvoiceNumber}}", HTML: "<p>{{t \"Total\"}}: {{rupiah .Total}}</p>"}
+	if err := ValidateTemplateSet(TemplateInvoice, valid, "id"); err != nil {
+		t.Errorf("valid template rejected: %v", err)
+	}
+
+	invalid := map[string]TemplateSet{
+		"syntax":        {Subject: "Invoice", HTML: "<p>{{if .Total}}</p>"},
+		"unknown field": {Subject: "Invoice {{.OrderCode}}", HTML: "<p></p>"},
+		// Only rendered for bank transfers, so validation must try both payment branches
+		"branch field": {Subject: "Invoice", HTML: `{{if eq .PaymentMethod "qris"}}QR{{else}}{{.BankName}}{{end}}`},
+	}
+	for name, set := range invalid {
+		if err := ValidateTemplateSet(TemplateInvoice, set, "id"); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestBuiltinTemplatesPassValidation(t *testing.T) {
+	for _, name := range TemplateNames() {
+		set, _ := BuiltinTemplate(name)
+		for _, lang := range []string{"id", "en"} {
+			if err := ValidateTemplateSet(name, set, lang); err != nil {
+				t.Errorf("%s/%s: %v", name, lang, err)
+			}
+		}
+	}
+}
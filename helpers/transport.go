@@ -0,0 +1,216 @@
+package helpers
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"gopkg.in/gomail.v2"
+)
+
+// Attachment is a file attached to an outgoing email.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message is a single outgoing email. Text is optional and sent as the plain-text alternative.
+type Message struct {
+	To          string
+	ToName      string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []Attachment
+}
+
+// Mailer delivers messages. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// NewMailer builds the transport selected by MAIL_TRANSPORT.
+func NewMailer(mail utils.Mail, credentials utils.Email) (Mailer, error) {
+	switch mail.Transport {
+	case "smtp":
+		return &SMTPMailer{
+			Host:               mail.Host,
+			Port:               mail.Port,
+			Username:           credentials.User,
+			Password:           credentials.Password,
+			From:               mail.From,
+			TLS:                mail.TLS,
+			InsecureSkipVerify: mail.InsecureSkipVerify,
+		}, nil
+	case "capture":
+		return NewCaptureMailer(mail.CaptureDir), nil
+	default:
+		return nil, fmt.Errorf("unknown mail transport %q", mail.Transport)
+	}
+}
+
+// SMTPMailer sends through an SMTP server.
+type SMTPMailer struct {
+	Host               string
+	Port               int
+	Username           string
+	Password           string
+	From               string
+	TLS                string // "ssl" for implicit TLS, otherwise STARTTLS when the server offers it
+	InsecureSkipVerify bool
+}
+
+func (m *SMTPMailer) Send(msg Message) error {
+	d := gomail.NewDialer(m.Host, m.Port, m.Username, m.Password)
+	d.SSL = m.TLS == "ssl"
+	d.TLSConfig = &tls.Config{ServerName: m.Host, InsecureSkipVerify: m.InsecureSkipVerify}
+
+	s, err := d.Dial()
+	if err != nil {
+		return fmt.Errorf("failed to dial SMTP server: %w", err)
+	}
+	defer s.Close()
+
+	if err := gomail.Send(s, buildMessage(m.From, msg)); err != nil {
+		return fmt.Errorf("could not send email to %q: %w", msg.To, err)
+	}
+	return nil
+}
+
+func buildMessage(from string, msg Message) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeader("From", from)
+	m.SetAddressHeader("To", msg.To, msg.ToName)
+	m.SetHeader("Subject", msg.Subject)
+	if msg.Text != "" {
+		m.SetBody("text/plain", msg.Text)
+		m.AddAlternative("text/html", msg.HTML)
+	} else {
+		m.SetBody("text/html", msg.HTML)
+	}
+
+	for _, attachment := range msg.Attachments {
+		data := attachment.Data
+		m.Attach(attachment.Filename, gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		}), gomail.SetHeader(map[string][]string{"Content-Type": {attachment.ContentType}}))
+	}
+	return m
+}
+
+// CapturedMessage is a message kept by CaptureMailer instead of being sent.
+type CapturedMessage struct {
+	ID          int       `json:"id"`
+	To          string    `json:"to"`
+	ToName      string    `json:"to_name"`
+	Subject     string    `json:"subject"`
+	HTML        string    `json:"-"`
+	Text        string    `json:"text,omitempty"`
+	Attachments []string  `json:"attachments"`
+	CapturedAt  time.Time `json:"captured_at"`
+}
+
+// captureLimit bounds memory use of a long running development server.
+const captureLimit = 100
+
+// CaptureMailer keeps messages in memory, and optionally writes them to Dir,
+// so local and test environments never send real email.
+type CaptureMailer struct {
+	Dir string
+
+	mu       sync.Mutex
+	nextID   int
+	messages []CapturedMessage
+}
+
+func NewCaptureMailer(dir string) *CaptureMailer {
+	return &CaptureMailer{Dir: dir, nextID: 1}
+}
+
+func (m *CaptureMailer) Send(msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	captured := CapturedMessage{
+		ID:          m.nextID,
+		To:          msg.To,
+		ToName:      msg.ToName,
+		Subject:     msg.Subject,
+		HTML:        msg.HTML,
+		Text:        msg.Text,
+		Attachments: []string{},
+		CapturedAt:  time.Now(),
+	}
+	for _, attachment := range msg.Attachments {
+		captured.Attachments = append(captured.Attachments, attachment.Filename)
+	}
+	m.nextID++
+
+	m.messages = append(m.messages, captured)
+	if len(m.messages) > captureLimit {
+		m.messages = m.messages[len(m.messages)-captureLimit:]
+	}
+
+	if m.Dir != "" {
+		return m.writeFiles(captured, msg.Attachments)
+	}
+	return nil
+}
+
+// Messages returns captured messages, newest first.
+func (m *CaptureMailer) Messages() []CapturedMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]CapturedMessage, len(m.messages))
+	for i, msg := range m.messages {
+		messages[len(m.messages)-1-i] = msg
+	}
+	return messages
+}
+
+// Message looks up a captured message by ID.
+func (m *CaptureMailer) Message(id int) (CapturedMessage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, msg := range m.messages {
+		if msg.ID == id {
+			return msg, true
+		}
+	}
+	return CapturedMessage{}, false
+}
+
+var unsafeFilename = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (m *CaptureMailer) writeFiles(msg CapturedMessage, attachments []Attachment) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create capture directory: %w", err)
+	}
+
+	prefix := fmt.Sprintf("%s-%03d-%s", msg.CapturedAt.Format("20060102-150405"), msg.ID, unsafeFilename.ReplaceAllString(msg.To, "_"))
+	if err := os.WriteFile(filepath.Join(m.Dir, prefix+".html"), []byte(msg.HTML), 0o644); err != nil {
+		return fmt.Errorf("failed to write captured email: %w", err)
+	}
+	if msg.Text != "" {
+		if err := os.WriteFile(filepath.Join(m.Dir, prefix+".txt"), []byte(msg.Text), 0o644); err != nil {
+			return fmt.Errorf("failed to write captured email: %w", err)
+		}
+	}
+	for _, attachment := range attachments {
+		name := prefix + "-" + unsafeFilename.ReplaceAllString(attachment.Filename, "_")
+		if err := os.WriteFile(filepath.Join(m.Dir, name), attachment.Data, 0o644); err != nil {
+			return fmt.Errorf("failed to write captured attachment: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCaptureMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer := NewCaptureMailer(dir)
+
+	for _, to := range []string{"first@example.com", "second@example.com"} {
+		if err := mailer.Send(Message{
+			To:          to,
+			Subject:     "Invoice for Your Purchase",
+			HTML:        "<p>Hello</p>",
+			Attachments: []Attachment{{Filename: "receipt.pdf", ContentType: "application/pdf", Data: []byte("%PDF")}},
+		}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	messages := mailer.Messages()
+	if len(messages) != 2 || messages[0].To != "second@example.com" {
+		t.Fatalf("expected newest message first, got %+v", messages)
+	}
+	if msg, ok := mailer.Message(messages[1].ID); !ok || msg.HTML != "<p>Hello</p>" {
+		t.Errorf("Message(%d) = %+v, %v", messages[1].ID, msg, ok)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(files) != 4 {
+		t.Errorf("expected html and attachment per message on disk, got %v", files)
+	}
+	for _, f := range files {
+		if info, err := os.Stat(f); err != nil || info.Size() == 0 {
+			t.Errorf("captured file %s is empty", f)
+		}
+	}
+}
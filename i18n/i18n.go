@@ -0,0 +1,142 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Supported languages. Messages are written in English and used as catalog keys,
+// so English needs no catalog of its own.
+const (
+	Indonesian = "id"
+	English    = "en"
+)
+
+// Default is used when neither the request nor the customer picks a language.
+// Most customers are Indonesian.
+const Default = Indonesian
+
+const contextKey = "lang"
+
+// Normalize maps a language tag such as "id-ID" or "en_US" to a supported
+// language, or returns "" when it is not supported.
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	switch tag {
+	case Indonesian, "in": // "in" is the legacy code still sent by older Android devices
+		return Indonesian
+	case English:
+		return English
+	}
+	return ""
+}
+
+// Resolve returns the first supported language out of the candidates, or Default.
+func Resolve(candidates ...string) string {
+	for _, candidate := range candidates {
+		if lang := Normalize(candidate); lang != "" {
+			return lang
+		}
+	}
+	return Default
+}
+
+// ParseAcceptLanguage returns the supported language with the highest weight in an
+// Accept-Language header, or "" when the header names none.
+func ParseAcceptLanguage(header string) string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+
+	var langs []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang := Normalize(tag)
+		if lang == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			langs = append(langs, weighted{lang, q})
+		}
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	if len(langs) == 0 {
+		return ""
+	}
+	return langs[0].lang
+}
+
+// Middleware picks the response language from the ?lang= query parameter,
+// falling back to the Accept-Language header.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := Resolve(c.Query("lang"), ParseAcceptLanguage(c.GetHeader("Accept-Language")))
+		c.Set(contextKey, lang)
+		c.Header("Content-Language", lang)
+		c.Next()
+	}
+}
+
+// FromContext returns the language chosen by Middleware.
+func FromContext(c *gin.Context) string {
+	if lang := c.GetString(contextKey); lang != "" {
+		return lang
+	}
+	return Default
+}
+
+// T translates an English message into lang. Args are applied with fmt.Sprintf
+// after translation. Messages missing from the catalog are returned untranslated.
+func T(lang, message string, args ...interface{}) string {
+	if translated, ok := catalog[lang][message]; ok {
+		message = translated
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// Error is an error whose message can be translated. Validation helpers return it
+// so handlers can report the failure in the caller's language.
+type Error struct {
+	Format string
+	Args   []interface{}
+}
+
+// Errorf returns an Error; format is the English catalog key.
+func Errorf(format string, args ...interface{}) error {
+	return &Error{Format: format, Args: args}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(e.Format, e.Args...)
+}
+
+// Translate returns err's message in lang when err is an Error, otherwise err.Error().
+func Translate(lang string, err error) string {
+	var translatable *Error
+	if errors.As(err, &translatable) {
+		return T(lang, translatable.Format, translatable.Args...)
+	}
+	return err.Error()
+}
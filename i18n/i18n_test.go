@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	cases := map[string]string{
+		"":                                    "",
+		"fr-FR":                               "",
+		"id-ID,id;q=0.9,en-US;q=0.8,en;q=0.7": Indonesian,
+		"en-US,en;q=0.9,id;q=0.8":             English,
+		"fr;q=1, en;q=0.5, id;q=0.7":          Indonesian,
+		"in":                                  Indonesian,
+		"en;q=0, id;q=0.1":                    Indonesian,
+	}
+	for header, want := range cases {
+		if got := ParseAcceptLanguage(header); got != want {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := T(Indonesian, "Product with ID %d not found", 7); got != "Produk dengan ID 7 tidak ditemukan" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := T(English, "Product with ID %d not found", 7); got != "Product with ID 7 not found" {
+		t.Errorf("English should use the message itself, got %q", got)
+	}
+	if got := Translate(Indonesian, Errorf("invalid timezone %q", "Mars/Base")); got != `zona waktu "Mars/Base" tidak valid` {
+		t.Errorf("unexpected error translation %q", got)
+	}
+}
+
+var verb = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogKeepsFormatVerbs(t *testing.T) {
+	for lang, messages := range catalog {
+		for key, translated := range messages {
+			if strings.Join(verb.FindAllString(key, -1), "") != strings.Join(verb.FindAllString(translated, -1), "") {
+				t.Errorf("%s: %q and %q use different format verbs", lang, key, translated)
+			}
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	UseJSONFieldNames()
+
+	var request struct {
+		Email  string `json:"email" binding:"required,email"`
+		Rating int    `json:"rating" binding:"min=1,max=5"`
+	}
+	request.Email = "not-an-email"
+	request.Rating = 9
+
+	err := binding.Validator.ValidateStruct(&request)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	want := []FieldError{
+		{Field: "email", Message: "email harus berupa alamat email yang valid"},
+		{Field: "rating", Message: "rating maksimal 5"},
+	}
+	if got := ValidationErrors(Indonesian, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidationErrors = %+v, want %+v", got, want)
+	}
+	if got := ValidationErrors(Indonesian, errors.New("unexpected EOF")); got != nil {
+		t.Errorf("unparseable body should have no fields, got %+v", got)
+	}
+}
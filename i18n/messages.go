@@ -0,0 +1,222 @@
+package i18n
+
+// catalog maps English messages to their translations. Keep the keys identical to
+// the English text passed to T, including fmt verbs.
+var catalog = map[string]map[string]string{
+	Indonesian: {
+		// Request validation
+		"Invalid request data":                                        "Data permintaan tidak valid",
+		"Request body is not valid JSON":                              "Isi permintaan bukan JSON yang valid",
+		"%s has the wrong type, expected %s":                          "%s memiliki tipe yang salah, seharusnya %s",
+		"%s is invalid":                                               "%s tidak valid",
+		"%s is required":                                              "%s wajib diisi",
+		"%s must be a valid email address":                            "%s harus berupa alamat email yang valid",
+		"%s must be at least %s":                                      "%s minimal %s",
+		"%s must be at least %s characters long":                      "%s minimal %s karakter",
+		"%s must be at most %s":                                       "%s maksimal %s",
+		"%s must be at most %s characters long":                       "%s maksimal %s karakter",
+		"%s must be greater than %s":                                  "%s harus lebih besar dari %s",
+		"%s must be less than %s":                                     "%s harus lebih kecil dari %s",
+		"%s must not contain duplicates":                              "%s tidak boleh berisi duplikat",
+		"%s must be one of: %s":                                       "%s harus salah satu dari: %s",
+		"invalid date %q, expected YYYY-MM-DD":                        "tanggal %q tidak valid, gunakan format YYYY-MM-DD",
+		"invalid time %q, expected HH:MM":                             "waktu %q tidak valid, gunakan format HH:MM",
+		"invalid from date, expected YYYY-MM-DD":                      "tanggal from tidak valid, gunakan format YYYY-MM-DD",
+		"invalid to date, expected YYYY-MM-DD":                        "tanggal to tidak valid, gunakan format YYYY-MM-DD",
+		"to must not be before from":                                  "to tidak boleh sebelum from",
+		"end_date must not be before start_date":                      "end_date tidak boleh sebelum start_date",
+		"invalid timezone %q":                                         "zona waktu %q tidak valid",
+		"invalid group_by %q":                                         "group_by %q tidak valid",
+		"unsupported export format %q":                                "format ekspor %q tidak didukung",
+		"menu not found":                                              "menu tidak ditemukan",
+		"category not found":                                          "kategori tidak ditemukan",
+		"Category %d does not exist":                                  "Kategori %d tidak ada",
+		"fixed_price must not be negative":                            "fixed_price tidak boleh negatif",
+		"discount_percent must be between 0 and 100":                  "discount_percent harus antara 0 dan 100",
+		"either fixed_price or discount_percent must be set":          "fixed_price atau discount_percent harus diisi",
+		"exactly one of menu_id or category_id must be set":           "isi salah satu dari menu_id atau category_id",
+		"invalid day of week %q, expected 0 (Sunday) to 6 (Saturday)": "hari %q tidak valid, gunakan 0 (Minggu) sampai 6 (Sabtu)",
+		"group_by must be one of day, week or month":                  "group_by harus salah satu dari day, week atau month",
+		"group_by must be one of day, week, month, menu, category or payment_method": "group_by harus salah satu dari day, week, month, menu, category atau payment_method",
+
+		// Authentication
+		"Authorization header is required": "Header Authorization wajib diisi",
+		"Invalid token format":             "Format token tidak valid",
+		"invalid token":                    "token tidak valid",
+		"token expired":                    "token sudah kedaluwarsa",
+		"Invalid Username or password":     "Username atau password salah",
+		"Login successful":                 "Login berhasil",
+		"Error generating token":           "Gagal membuat token",
+
+		// Invalid identifiers and missing records
+		"Invalid params id":            "Parameter id tidak valid",
+		"Invalid request id":           "ID permintaan tidak valid",
+		"Invalid order ID":             "ID pesanan tidak valid",
+		"Invalid product ID":           "ID produk tidak valid",
+		"Invalid email ID":             "ID email tidak valid",
+		"Invalid review ID":            "ID ulasan tidak valid",
+		"Invalid schedule ID":          "ID jadwal tidak valid",
+		"Invalid price rule ID":        "ID aturan harga tidak valid",
+		"Order not found":              "Pesanan tidak ditemukan",
+		"Order item not found":         "Item pesanan tidak ditemukan",
+		"Product not found":            "Produk tidak ditemukan",
+		"Category not found":           "Kategori tidak ditemukan",
+		"Payment method not found":     "Metode pembayaran tidak ditemukan",
+		"Email not found":              "Email tidak ditemukan",
+		"Review not found":             "Ulasan tidak ditemukan",
+		"Schedule not found":           "Jadwal tidak ditemukan",
+		"Price rule not found":         "Aturan harga tidak ditemukan",
+		"No failed email with this ID": "Tidak ada email gagal dengan ID ini",
+		"Route not found":              "Rute tidak ditemukan",
+
+		"Too many requests, try again in %d seconds": "Terlalu banyak permintaan, coba lagi dalam %d detik",
+
+		// Unexpected failures, the cause is only logged
+		"Internal server error": "Terjadi kesalahan server",
+
+		// Checkout and orders
+		"Product with ID %d not found":                                     "Produk dengan ID %d tidak ditemukan",
+		"Product %s is not available at this time":                         "Produk %s tidak tersedia saat ini",
+		"Quantity must be greater than 0":                                  "Jumlah harus lebih dari 0",
+		"Internal Server Error: Payment":                                   "Terjadi kesalahan server: Pembayaran",
+		"Error creating order":                                             "Gagal membuat pesanan",
+		"Successfully created transaction":                                 "Transaksi berhasil dibuat",
+		"Failed to fetch order":                                            "Gagal mengambil pesanan",
+		"Failed to fetch orders":                                           "Gagal mengambil daftar pesanan",
+		"Failed to fetch payment method":                                   "Gagal mengambil metode pembayaran",
+		"Failed to update order status":                                    "Gagal memperbarui status pesanan",
+		"Failed to render receipt":                                         "Gagal membuat kuitansi",
+		"Order status updated successfully":                                "Status pesanan berhasil diperbarui",
+		"Successfully updated order status":                                "Status pesanan berhasil diperbarui",
+		"Order already has this status":                                    "Pesanan sudah memiliki status ini",
+		"Only pending orders can be marked as paid":                        "Hanya pesanan yang menunggu pembayaran yang dapat ditandai lunas",
+		"Only pending orders can be cancelled, refund paid orders instead": "Hanya pesanan yang menunggu pembayaran yang dapat dibatalkan, lakukan pengembalian dana untuk pesanan yang sudah dibayar",
+		"Only paid orders can be refunded":                                 "Hanya pesanan yang sudah dibayar yang dapat dikembalikan dananya",
+		"Error fetching all payment methods":                               "Gagal mengambil daftar metode pembayaran",
+
+		// Menus and categories
+		"Error fetching products":                "Gagal mengambil daftar produk",
+		"Failed to fetch all menu items":         "Gagal mengambil semua menu",
+		"Failed to fetch menu items by category": "Gagal mengambil menu berdasarkan kategori",
+		"Failed to fetch menu items":             "Gagal mengambil menu",
+		"Error fetching category":                "Gagal mengambil kategori",
+		"Error fetching all categories":          "Gagal mengambil daftar kategori",
+		"Error creating new menu":                "Gagal membuat menu baru",
+		"Error creating new category":            "Gagal membuat kategori baru",
+		"Error updating product":                 "Gagal memperbarui produk",
+		"Error updating category":                "Gagal memperbarui kategori",
+		"Successfully created product":           "Produk berhasil dibuat",
+		"Successfully updated product":           "Produk berhasil diperbarui",
+		"Successfully created category":          "Kategori berhasil dibuat",
+		"Successfully updated category":          "Kategori berhasil diperbarui",
+
+		// Schedules and price rules
+		"Error fetching schedules":        "Gagal mengambil daftar jadwal",
+		"Error fetching schedule":         "Gagal mengambil jadwal",
+		"Error creating schedule":         "Gagal membuat jadwal",
+		"Error updating schedule":         "Gagal memperbarui jadwal",
+		"Error deleting schedule":         "Gagal menghapus jadwal",
+		"Successfully created schedule":   "Jadwal berhasil dibuat",
+		"Successfully updated schedule":   "Jadwal berhasil diperbarui",
+		"Successfully deleted schedule":   "Jadwal berhasil dihapus",
+		"Error fetching price rules":      "Gagal mengambil daftar aturan harga",
+		"Error fetching price rule":       "Gagal mengambil aturan harga",
+		"Error creating price rule":       "Gagal membuat aturan harga",
+		"Error updating price rule":       "Gagal memperbarui aturan harga",
+		"Error deleting price rule":       "Gagal menghapus aturan harga",
+		"Successfully created price rule": "Aturan harga berhasil dibuat",
+		"Successfully updated price rule": "Aturan harga berhasil diperbarui",
+		"Successfully deleted price rule": "Aturan harga berhasil dihapus",
+
+		// Reviews
+		"Only paid orders can be reviewed":    "Hanya pesanan yang sudah dibayar yang dapat diulas",
+		"Order has not been paid":             "Pesanan belum dibayar",
+		"This item has already been reviewed": "Item ini sudah diulas",
+		"Failed to check existing review":     "Gagal memeriksa ulasan yang ada",
+		"Failed to fetch order item":          "Gagal mengambil item pesanan",
+		"Failed to fetch reviews":             "Gagal mengambil daftar ulasan",
+		"Failed to fetch review":              "Gagal mengambil ulasan",
+		"Error creating review":               "Gagal membuat ulasan",
+		"Error updating review":               "Gagal memperbarui ulasan",
+		"Error deleting review":               "Gagal menghapus ulasan",
+		"Error updating menu rating":          "Gagal memperbarui rating menu",
+		"Successfully submitted review":       "Ulasan berhasil dikirim",
+		"Successfully updated review":         "Ulasan berhasil diperbarui",
+		"Successfully deleted review":         "Ulasan berhasil dihapus",
+
+		// Reports, exports and emails
+		"Failed to build sales report": "Gagal menyusun laporan penjualan",
+		"Failed to export orders":      "Gagal mengekspor pesanan",
+		"Failed to fetch emails":       "Gagal mengambil daftar email",
+		"Error resending email":        "Gagal mengirim ulang email",
+		"Email queued for resending":   "Email dijadwalkan untuk dikirim ulang",
+
+		// Email templates
+		"Email template not found":          "Template email tidak ditemukan",
+		"Failed to fetch email templates":   "Gagal mengambil daftar template email",
+		"Template is invalid: %v":           "Template tidak valid: %v",
+		"Error saving email template":       "Gagal menyimpan template email",
+		"Error rendering email template":    "Gagal menyusun template email",
+		"Successfully saved email template": "Template email berhasil disimpan",
+
+		// Invoice and receipt
+		"Invoice":                   "Faktur",
+		"INVOICE":                   "FAKTUR",
+		"Invoice for Your Purchase": "Faktur untuk Pembelian Anda",
+		"Invoice Date":              "Tanggal Faktur",
+		"Invoice Number":            "Nomor Faktur",
+		"FROM":                      "DARI",
+		"BILL TO":                   "DITAGIHKAN KEPADA",
+		"Payment Information":       "Informasi Pembayaran",
+		"Payment Status":            "Status Pembayaran",
+		"QRIS Payment":              "Pembayaran QRIS",
+		"QRIS QR Code":              "Kode QR QRIS",
+		"Bank Transfer":             "Transfer Bank",
+		"Account Name":              "Nama Rekening",
+		"Account Number":            "Nomor Rekening",
+		"Product":                   "Produk",
+		"Product/Service":           "Produk/Layanan",
+		"Quantity":                  "Jumlah",
+		"Unit Price":                "Harga Satuan",
+		"Note":                      "Catatan",
+		"Subtotal":                  "Subtotal",
+		"Discount (included above)": "Diskon (sudah termasuk di atas)",
+		"Tax":                       "Pajak",
+		"Tax (if applicable)":       "Pajak (jika berlaku)",
+		"Total":                     "Total",
+		"Scan the QR code to complete your payment.":        "Pindai kode QR untuk menyelesaikan pembayaran Anda.",
+		"Scan the QR code to complete your payment: %s":     "Pindai kode QR untuk menyelesaikan pembayaran Anda: %s",
+		"QRIS - scan the QR code to complete your payment.": "QRIS - pindai kode QR untuk menyelesaikan pembayaran Anda.",
+		"QR code unavailable, open: %s":                     "Kode QR tidak tersedia, buka: %s",
+		"Transfer to %s (%s).":                              "Transfer ke %s (%s).",
+
+		// Payment and order statuses shown on receipts
+		"pending":          "menunggu pembayaran",
+		"success":          "lunas",
+		"captured":         "lunas",
+		"expired":          "kedaluwarsa",
+		"canceled":         "dibatalkan",
+		"refunded":         "dikembalikan",
+		"ready_for_pickup": "siap diambil",
+		"completed":        "selesai",
+
+		// Lifecycle emails
+		"Order Number":                     "Nomor Pesanan",
+		"Hi %s,":                           "Halo %s,",
+		"Payment Received":                 "Pembayaran Diterima",
+		"Payment Expired":                  "Pembayaran Kedaluwarsa",
+		"Ready for Pickup":                 "Siap Diambil",
+		"Order Cancelled":                  "Pesanan Dibatalkan",
+		"Refund Issued":                    "Dana Dikembalikan",
+		"Payment received for order %s":    "Pembayaran untuk pesanan %s telah diterima",
+		"Payment for order %s has expired": "Pembayaran untuk pesanan %s telah kedaluwarsa",
+		"Order %s is ready for pickup":     "Pesanan %s siap diambil",
+		"Order %s has been cancelled":      "Pesanan %s telah dibatalkan",
+		"Refund issued for order %s":       "Dana untuk pesanan %s telah dikembalikan",
+		"We have received your payment. Your order is now being prepared.":                                        "Kami telah menerima pembayaran Anda. Pesanan Anda sedang disiapkan.",
+		"We did not receive your payment in time, so this order has been closed. Feel free to place a new order.": "Kami tidak menerima pembayaran Anda tepat waktu, sehingga pesanan ini ditutup. Silakan buat pesanan baru.",
+		"Good news, your order is ready! Show this order number at the counter to pick it up.":                    "Kabar baik, pesanan Anda sudah siap! Tunjukkan nomor pesanan ini di kasir untuk mengambilnya.",
+		"Your order has been cancelled. If you did not expect this, please contact us.":                           "Pesanan Anda telah dibatalkan. Jika Anda tidak merasa membatalkannya, silakan hubungi kami.",
+		"We have issued a refund for this order. It may take a few business days to appear in your account.":      "Kami telah mengembalikan dana untuk pesanan ini. Dana mungkin memerlukan beberapa hari kerja untuk masuk ke rekening Anda.",
+	},
+}
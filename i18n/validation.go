@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// UseJSONFieldNames makes validation errors report the JSON name of a field
+// (e.g. "order_detail_id") instead of the Go struct field name.
+func UseJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
+
+// FieldError is one invalid field of a request body.
+type FieldError struct {
+	Field   string `json:"field"` // JSON path, e.g. products[0].quantity
+	Message string `json:"message"`
+}
+
+// ValidationErrors describes a ShouldBindJSON error in lang, one entry per invalid field.
+// It returns nil when the body could not be parsed at all.
+func ValidationErrors(lang string, err error) []FieldError {
+	var fieldErrors validator.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		fields := make([]FieldError, 0, len(fieldErrors))
+		for _, fieldError := range fieldErrors {
+			fields = append(fields, FieldError{Field: fieldPath(fieldError), Message: fieldMessage(lang, fieldError)})
+		}
+		return fields
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return []FieldError{{
+			Field:   typeError.Field,
+			Message: T(lang, "%s has the wrong type, expected %s", typeError.Field, typeError.Type.String()),
+		}}
+	}
+	return nil
+}
+
+// fieldPath returns the JSON path of the field. The namespace starts with the name of the
+// struct being bound, unless it is anonymous, which is the only segment that is the same
+// in the JSON and Go namespaces.
+func fieldPath(fe validator.FieldError) string {
+	root, path, ok := strings.Cut(fe.Namespace(), ".")
+	if ok && strings.HasPrefix(fe.StructNamespace(), root+".") {
+		return path
+	}
+	return fe.Namespace()
+}
+
+func fieldMessage(lang string, fe validator.FieldError) string {
+	field := fe.Field()
+	isString := fe.Kind() == reflect.String
+
+	switch fe.Tag() {
+	case "required":
+		return T(lang, "%s is required", field)
+	case "email":
+		return T(lang, "%s must be a valid email address", field)
+	case "oneof":
+		return T(lang, "%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "min", "gte":
+		if isString {
+			return T(lang, "%s must be at least %s characters long", field, fe.Param())
+		}
+		return T(lang, "%s must be at least %s", field, fe.Param())
+	case "max", "lte":
+		if isString {
+			return T(lang, "%s must be at most %s characters long", field, fe.Param())
+		}
+		return T(lang, "%s must be at most %s", field, fe.Param())
+	case "gt":
+		return T(lang, "%s must be greater than %s", field, fe.Param())
+	case "lt":
+		return T(lang, "%s must be less than %s", field, fe.Param())
+	case "unique":
+		return T(lang, "%s must not contain duplicates", field)
+	}
+	return T(lang, "%s is invalid", field)
+}
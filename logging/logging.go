@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID from clients, back in responses and on to Midtrans.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "" when there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// New returns a JSON logger writing records at level or above to w. Records logged with
+// a context carry its request ID, secrets and personal data are redacted.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})})
+}
+
+// contextHandler adds the request ID and trace of the context to every record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Redacted replaces the value of attributes that must never reach the logs.
+const Redacted = "[REDACTED]"
+
+// redactedKeys are attribute keys whose values are secrets or identify a customer.
+// Email addresses are masked wherever they appear instead, see maskEmails.
+var redactedKeys = map[string]bool{
+	"password":       true,
+	"token":          true,
+	"authorization":  true,
+	"cookie":         true,
+	"secret":         true,
+	"server_key":     true,
+	"secret_key_jwt": true,
+	"qr_string":      true,
+	"name":           true,
+	"customer_name":  true,
+	"recipient_name": true,
+	"first_name":     true,
+	"last_name":      true,
+	"phone":          true,
+}
+
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	if redactedKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, maskEmails(attr.Value.String()))
+	case slog.KindAny:
+		// Errors often quote the data they failed on
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, maskEmails(err.Error()))
+		}
+		if s, ok := attr.Value.Any().(fmt.Stringer); ok {
+			return slog.String(attr.Key, maskEmails(s.String()))
+		}
+	}
+	return attr
+}
+
+var emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// maskEmails keeps the first character and domain of every email address in s, which is
+// enough to tell customers apart while debugging: budi@example.com becomes b***@example.com.
+func maskEmails(s string) string {
+	return emailPattern.ReplaceAllString(s, "$1***@$2")
+}
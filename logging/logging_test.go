@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.InfoContext(ctx, "Checkout",
+		"password", "hunter2",
+		"Authorization", "Basic c2VjcmV0Og==",
+		"name", "Budi Santoso",
+		"recipient", "budi@example.com",
+		"error", errors.New("smtp rejected budi.s@example.co.id"),
+		"order_id", 42,
+	)
+	logger.Debug("Hidden below the level")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("want exactly one JSON record, got %q: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"request_id":    "req-1",
+		"password":      Redacted,
+		"Authorization": Redacted,
+		"name":          Redacted,
+		"recipient":     "b***@example.com",
+		"error":         "smtp rejected b***@example.co.id",
+		"order_id":      float64(42),
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s: got %v, want %v", key, record[key], value)
+		}
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(AssignRequestID())
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, RequestID(c.Request.Context()))
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		reused   bool
+	}{
+		{"reuses a valid id", "abc-123_DEF.4", true},
+		{"generates when missing", "", false},
+		{"replaces a malformed id", "bad id\nwith newline", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestIDHeader, tt.incoming)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			got := w.Header().Get(RequestIDHeader)
+			if got == "" || got != w.Body.String() {
+				t.Fatalf("header %q and context %q must carry the same id", got, w.Body.String())
+			}
+			if (got == tt.incoming) != tt.reused {
+				t.Errorf("got %q for incoming %q", got, tt.incoming)
+			}
+		})
+	}
+}
@@ -1,136 +1,180 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
-	"strings"
-
-	"github.com/dimassfeb-09/pestapasta-be/controllers"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Schedules and reports need Asia/Jakarta even on images without tzdata
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/logging"
+	"github.com/dimassfeb-09/pestapasta-be/metrics"
+	"github.com/dimassfeb-09/pestapasta-be/migrations"
 	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/router"
+	"github.com/dimassfeb-09/pestapasta-be/services"
+	"github.com/dimassfeb-09/pestapasta-be/tracing"
 	"github.com/dimassfeb-09/pestapasta-be/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/jinzhu/gorm"
 )
 
-var db *gorm.DB
+// shutdownTimeout bounds the graceful drain. Keep it below kill_timeout in fly.toml.
+const shutdownTimeout = 25 * time.Second
 
-func init() {
-	var err error
-	// Initialize the DB
-	db, err = models.InitializeDB()
+func main() {
+	slog.SetDefault(logging.New(os.Stderr, slog.LevelInfo))
 
+	// Load configuration once, a missing secret stops the server before it takes traffic
+	cfg, err := utils.LoadConfig("")
 	if err != nil {
-		log.Fatal("Failed to connect to the database:", err)
+		fatal("Invalid configuration", err)
 	}
-}
-
-func main() {
-	r := gin.Default()
-
-	// Setup CORS
-	utils.Cors(r)
-
-	// Group untuk endpoint publik (tidak memerlukan autentikasi)
-	public := r.Group("/")
-	{
-		public.POST("/login", func(c *gin.Context) {
-			controllers.HandleLogin(c, db)
-		})
+	logger := logging.New(os.Stderr, cfg.LogLevel)
+	slog.SetDefault(logger)
 
-		public.POST("/checkout", func(c *gin.Context) {
-			controllers.HandleCheckout(c, db)
-		})
-
-		public.GET("/menus", func(c *gin.Context) {
-			controllers.GetMenu(c, db)
-		})
-
-		public.GET("/menus/:id", func(c *gin.Context) {
-			controllers.GetMenuByID(c, db)
-		})
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.AppEnv)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
 
-		public.GET("/categories", func(c *gin.Context) {
-			controllers.GetCategories(c, db)
-		})
+	// Initialize the DB
+	db, err := models.InitializeDB(cfg.Database)
+	if err != nil {
+		fatal("Failed to connect to the database", err)
+	}
 
-		public.GET("/categories/:id", func(c *gin.Context) {
-			controllers.GetCategoriesByID(c, db)
-		})
+	// `run-app migrate ...` and `run-app seed ...` manage the database and exit,
+	// anything else starts the server
+	if len(os.Args) > 1 && (os.Args[1] == "migrate" || os.Args[1] == "seed") {
+		if os.Args[1] == "migrate" {
+			err = runMigrate(context.Background(), db.DB(), os.Args[2:])
+		} else {
+			err = runSeed(db, cfg, os.Args[2:])
+		}
+		db.Close()
+		if err != nil {
+			fatal("Command failed", err)
+		}
+		return
+	}
 
-		public.GET("/payment_methods", func(c *gin.Context) {
-			controllers.GetPaymentMethods(c, db)
-		})
+	// Refuse to serve against a schema this build does not understand
+	if err := migrations.Check(context.Background(), db.DB()); err != nil {
+		fatal("Database schema check failed", err)
+	}
 
-		public.GET("/orders/:id/status", func(c *gin.Context) {
-			controllers.CheckOrderStatusByID(c, db)
-		})
+	metrics.RegisterDB(db.DB())
+	tracing.RegisterGormCallbacks(db)
 
-		public.GET("/orders/:id", func(c *gin.Context) {
-			controllers.GetOrderByID(c, db)
-		})
+	midtrans := services.NewMidtransClient(cfg.Midtrans)
+	app := router.NewContainer(cfg, db, midtrans)
 
+	r := gin.New()
+	// Rate limits are per client IP, which only the load balancer's header knows
+	switch cfg.TrustedPlatform {
+	case "flyio":
+		r.TrustedPlatform = gin.PlatformFlyIO
+	case "cloudflare":
+		r.TrustedPlatform = gin.PlatformCloudflare
+	}
+	if err := r.SetTrustedProxies(nil); err != nil {
+		fatal("Failed to configure trusted proxies", err)
 	}
+	// Request IDs and spans come first so access logs, panics and Midtrans calls all carry them
+	r.Use(logging.AssignRequestID(), tracing.Middleware(), logging.AccessLog(logger), logging.Recovery(logger))
+	r.Use(metrics.Middleware())
+	r.Use(i18n.Middleware(), apperror.Middleware())
+	r.NoRoute(func(c *gin.Context) {
+		apperror.Abort(c, apperror.NotFound("Route not found"))
+	})
+	i18n.UseJSONFieldNames()
 
-	// Middleware untuk validasi JWT
-	authMiddleware := func(ctx *gin.Context) {
-		// Ambil header Authorization
-		authorization := ctx.GetHeader("Authorization")
-		if authorization == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			ctx.Abort()
-			return
-		}
+	// Setup CORS
+	utils.Cors(r)
 
-		// Periksa format Bearer
-		if !strings.HasPrefix(authorization, "Bearer ") {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
-			ctx.Abort()
-			return
-		}
+	// Deliver queued emails in the background
+	mailer, err := helpers.NewMailer(cfg.Mail, cfg.Email)
+	if err != nil {
+		fatal("Failed to configure mailer", err)
+	}
+	dispatcherOptions := services.DefaultDispatcherOptions
+	dispatcherOptions.Company = cfg.Company
+
+	// Cancelled on SIGINT or SIGTERM, which Fly sends before stopping a machine
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		services.RunEmailDispatcher(ctx, db, mailer, dispatcherOptions)
+	}()
+
+	// Let developers read captured emails without sending real ones
+	if capture, ok := mailer.(*helpers.CaptureMailer); ok && !cfg.IsProduction() {
+		app.CapturedEmails = capture
+	}
+	router.Register(r, app)
 
-		// Ekstrak token
-		tokenString := strings.TrimPrefix(authorization, "Bearer ")
+	// Start the server
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", "addr", srv.Addr, "version", utils.GetBuildInfo().Version)
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		fatal("Server failed", err)
+	case <-ctx.Done():
+	}
+	stop()
+	slog.Info("Shutting down, draining in-flight requests")
+	app.Draining.Store(true)
 
-		// Validasi token
-		claims, err := utils.ValidateJWT(tokenString)
-		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			ctx.Abort()
-			return
-		}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
 
-		// Simpan klaim ke context untuk digunakan pada handler berikutnya
-		ctx.Set("claims", claims)
-		ctx.Next()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error shutting down server", "error", err)
 	}
 
-	// Group untuk endpoint yang memerlukan autentikasi
-	auth := r.Group("/")
-	auth.Use(authMiddleware)
-	{
-		auth.GET("/orders", func(c *gin.Context) {
-			controllers.GetAllOrders(c, db)
-		})
-
-		auth.POST("/menus", func(c *gin.Context) {
-			controllers.CreateNewProduct(c, db)
-		})
-
-		auth.PUT("/menus/:id", func(c *gin.Context) {
-			controllers.UpdateProduct(c, db)
-		})
-
-		auth.POST("/categories", func(c *gin.Context) {
-			controllers.CreateCategory(c, db)
-		})
-
-		auth.PUT("/categories/:id", func(c *gin.Context) {
-			controllers.UpdateCategory(c, db)
-		})
+	// The dispatcher finishes the email it is sending, then returns because ctx is done
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		slog.Warn("Background workers did not stop in time")
 	}
 
-	// Start the server
-	if err := r.Run(":8081"); err != nil {
-		log.Fatal("Server failed:", err)
+	if err := db.Close(); err != nil {
+		slog.Error("Error closing database", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Error flushing traces", "error", err)
 	}
+	slog.Info("Shutdown complete")
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric served on /metrics. A registry of our own keeps
+// metrics registered by libraries out of the scrape.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration is labelled with the route template, e.g. /orders/:id, so
+	// the number of series does not grow with IDs.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "pestapasta_http_request_duration_seconds",
+		Help: "Time taken to handle HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	// Checkouts counts checkout attempts by payment method code and Outcome.
+	Checkouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pestapasta_checkouts_total",
+		Help: "Checkout attempts by payment method and outcome.",
+	}, []string{"payment_method", "outcome"})
+
+	// MidtransRequestDuration is labelled with the status_code Midtrans returns, which
+	// differs from the HTTP status for status checks, or "error" when no answer arrived.
+	MidtransRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pestapasta_midtrans_request_duration_seconds",
+		Help:    "Time taken by Midtrans API calls.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"operation", "status_code"})
+
+	// EmailSends counts delivery attempts by email kind and outcome: sent, failed
+	// (retried later) or dead.
+	EmailSends = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pestapasta_email_sends_total",
+		Help: "Email delivery attempts by kind and outcome.",
+	}, []string{"kind", "outcome"})
+)
+
+// Checkout outcomes.
+const (
+	OutcomeSuccess       = "success"
+	OutcomeRejected      = "rejected"       // Business rule, e.g. unknown product or bad quantity
+	OutcomePaymentFailed = "payment_failed" // Midtrans refused or could not be reached
+	OutcomeError         = "error"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		Checkouts,
+		MidtransRequestDuration,
+		EmailSends,
+	)
+}
+
+// RegisterDB exposes the connection pool statistics of db.
+func RegisterDB(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// ObserveMidtrans records a Midtrans call that started at start.
+func ObserveMidtrans(operation, statusCode string, start time.Time) {
+	MidtransRequestDuration.WithLabelValues(operation, statusCode).Observe(time.Since(start).Seconds())
+}
+
+// Middleware records the duration and status of every request.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
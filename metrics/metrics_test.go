@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddlewareLabelsRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/orders/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/metrics", gin.WrapH(Handler()))
+
+	for _, path := range []string{"/orders/1", "/orders/2", "/nope"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.CollectAndCount(HTTPRequestDuration); got != 2 {
+		t.Errorf("got %d series, want one for /orders/:id and one for unmatched", got)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, `pestapasta_http_request_duration_seconds_count{method="GET",route="/orders/:id",status="200"} 2`) {
+		t.Errorf("route template series missing from:\n%s", body)
+	}
+	if strings.Contains(body, `route="/orders/1"`) {
+		t.Errorf("raw path used as a label")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dimassfeb-09/pestapasta-be/migrations"
+)
+
+const migrateUsage = "usage: run-app migrate up [n] | down [n] | status"
+
+// runMigrate handles `run-app migrate up [n]`, `run-app migrate down [n]` and
+// `run-app migrate status`. up applies every pending migration by default, down
+// rolls back one.
+func runMigrate(ctx context.Context, db *sql.DB, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(migrateUsage)
+	}
+
+	n := 0
+	if args[0] == "down" {
+		n = 1
+	}
+	if len(args) == 2 {
+		var err error
+		if n, err = strconv.Atoi(args[1]); err != nil || n <= 0 {
+			return fmt.Errorf("invalid migration count %q, %s", args[1], migrateUsage)
+		}
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(ctx, db, n)
+		for _, migration := range applied {
+			fmt.Printf("Applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		return err
+	case "down":
+		rolledBack, err := migrations.Down(ctx, db, n)
+		for _, migration := range rolledBack {
+			fmt.Printf("Rolled back %04d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrations.Status(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
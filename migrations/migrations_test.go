@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Load() returned no migrations")
+	}
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("migration %d has version %d", i, migration.Version)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s has an empty up or down script", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestInitialSchemaCreatesEveryTable(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tables := []string{
+		"users", "categories", "menus", "orders", "order_details", "payments", "payment_methods",
+		"availability_schedules", "price_rules", "reviews", "email_outbox", "email_templates",
+	}
+	for _, table := range tables {
+		if !strings.Contains(migrations[0].Up, "CREATE TABLE IF NOT EXISTS "+table+" (") {
+			t.Errorf("initial schema does not create %s", table)
+		}
+		if !strings.Contains(migrations[0].Down, "DROP TABLE IF EXISTS "+table+";") {
+			t.Errorf("initial schema rollback does not drop %s", table)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS email_templates;
+DROP TABLE IF EXISTS email_outbox;
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS price_rules;
+DROP TABLE IF EXISTS availability_schedules;
+DROP TABLE IF EXISTS payment_methods;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS order_details;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS menus;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
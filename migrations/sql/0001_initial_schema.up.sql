@@ -0,0 +1,163 @@
+-- Schema previously created by gorm AutoMigrate. Every statement is idempotent so
+-- databases that were set up by AutoMigrate adopt this migration without changes.
+
+CREATE TABLE IF NOT EXISTS users (
+    id serial PRIMARY KEY,
+    name varchar(255),
+    username varchar(255),
+    password varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id serial PRIMARY KEY,
+    category_name varchar(255),
+    description varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS menus (
+    id serial PRIMARY KEY,
+    name varchar(255),
+    price numeric,
+    description varchar(255),
+    category_id integer,
+    image_url varchar(255),
+    rating integer,
+    rating_average numeric,
+    rating_count integer,
+    is_available boolean
+);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id serial PRIMARY KEY,
+    order_date varchar(255),
+    email varchar(255),
+    name varchar(255),
+    total_price numeric,
+    discount numeric,
+    tax numeric,
+    order_status varchar(255),
+    language varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS order_details (
+    id serial PRIMARY KEY,
+    order_id integer,
+    menu_id integer,
+    quantity integer,
+    unit_price numeric,
+    discount numeric,
+    subtotal_price numeric,
+    notes varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+    id serial PRIMARY KEY,
+    order_id integer,
+    payment_method varchar(255),
+    payment_status varchar(255),
+    payment_account_number varchar(255),
+    payment_date varchar(255),
+    created_at varchar(255),
+    transaction_code varchar(255),
+    payment_account_name varchar(255),
+    payment_qr_code_url varchar(255),
+    payment_qr_string text,
+    payment_create_date varchar(255),
+    payment_expired_date varchar(255),
+    payment_transaction_id varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id serial PRIMARY KEY,
+    name varchar(255),
+    account_number varchar(255),
+    account_name varchar(255),
+    code varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS availability_schedules (
+    id serial PRIMARY KEY,
+    menu_id integer,
+    category_id integer,
+    days_of_week varchar(255),
+    start_time varchar(255),
+    end_time varchar(255),
+    start_date varchar(255),
+    end_date varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS price_rules (
+    id serial PRIMARY KEY,
+    name varchar(255),
+    menu_id integer,
+    category_id integer,
+    discount_percent numeric,
+    fixed_price numeric,
+    days_of_week varchar(255),
+    start_time varchar(255),
+    end_time varchar(255),
+    start_date varchar(255),
+    end_date varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id serial PRIMARY KEY,
+    order_id integer,
+    order_detail_id integer,
+    menu_id integer,
+    name varchar(255),
+    rating integer,
+    comment text,
+    status varchar(255),
+    created_at varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id serial PRIMARY KEY,
+    kind varchar(255),
+    order_id integer,
+    recipient varchar(255),
+    recipient_name varchar(255),
+    subject varchar(255),
+    body text,
+    text_body text,
+    status varchar(255),
+    attempts integer,
+    next_attempt_at timestamp with time zone,
+    last_error text,
+    created_at timestamp with time zone,
+    sent_at timestamp with time zone
+);
+
+CREATE TABLE IF NOT EXISTS email_templates (
+    id serial PRIMARY KEY,
+    name varchar(255),
+    language varchar(255),
+    version integer,
+    subject varchar(255),
+    html text,
+    text text,
+    created_by varchar(255),
+    created_at timestamp with time zone
+);
+
+-- Columns added to existing tables while AutoMigrate was still in use
+ALTER TABLE menus ADD COLUMN IF NOT EXISTS rating_average numeric;
+ALTER TABLE menus ADD COLUMN IF NOT EXISTS rating_count integer;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount numeric;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax numeric;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS language varchar(255);
+ALTER TABLE order_details ADD COLUMN IF NOT EXISTS unit_price numeric;
+ALTER TABLE order_details ADD COLUMN IF NOT EXISTS discount numeric;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS payment_qr_string text;
+
+-- AutoMigrate created these as varchar(255), too short for QRIS payloads and 1000 character reviews
+ALTER TABLE payments ALTER COLUMN payment_qr_string TYPE text;
+ALTER TABLE reviews ALTER COLUMN comment TYPE text;
+
+-- Indexes AutoMigrate created from struct tags, under the names it used
+CREATE UNIQUE INDEX IF NOT EXISTS uix_reviews_order_detail_id ON reviews (order_detail_id);
+CREATE INDEX IF NOT EXISTS idx_reviews_menu_id ON reviews (menu_id);
+CREATE INDEX IF NOT EXISTS idx_email_outbox_status ON email_outbox (status);
+CREATE INDEX IF NOT EXISTS idx_email_outbox_next_attempt_at ON email_outbox (next_attempt_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_template_version ON email_templates (name, language, version);
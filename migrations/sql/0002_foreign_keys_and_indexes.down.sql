@@ -0,0 +1,11 @@
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS fk_reviews_menu;
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS fk_reviews_order_detail;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS fk_payments_order;
+ALTER TABLE order_details DROP CONSTRAINT IF EXISTS fk_order_details_menu;
+ALTER TABLE order_details DROP CONSTRAINT IF EXISTS fk_order_details_order;
+ALTER TABLE menus DROP CONSTRAINT IF EXISTS fk_menus_category;
+
+DROP INDEX IF EXISTS idx_order_details_order_id;
+DROP INDEX IF EXISTS uix_payments_transaction_code;
+DROP INDEX IF EXISTS idx_payments_order_id;
+DROP INDEX IF EXISTS idx_orders_email;
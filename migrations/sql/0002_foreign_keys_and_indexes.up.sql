@@ -0,0 +1,23 @@
+-- Lookups by customer email, by order and by transaction code were full table scans
+CREATE INDEX IF NOT EXISTS idx_orders_email ON orders (email);
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uix_payments_transaction_code ON payments (transaction_code);
+CREATE INDEX IF NOT EXISTS idx_order_details_order_id ON order_details (order_id);
+
+-- NOT VALID enforces the constraints for new rows without failing on orphans left in
+-- existing data. Run ALTER TABLE ... VALIDATE CONSTRAINT once those are cleaned up.
+ALTER TABLE menus
+    ADD CONSTRAINT fk_menus_category FOREIGN KEY (category_id) REFERENCES categories (id) NOT VALID;
+ALTER TABLE order_details
+    ADD CONSTRAINT fk_order_details_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE NOT VALID;
+ALTER TABLE order_details
+    ADD CONSTRAINT fk_order_details_menu FOREIGN KEY (menu_id) REFERENCES menus (id) NOT VALID;
+ALTER TABLE payments
+    ADD CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE NOT VALID;
+ALTER TABLE reviews
+    ADD CONSTRAINT fk_reviews_order_detail FOREIGN KEY (order_detail_id) REFERENCES order_details (id) ON DELETE CASCADE NOT VALID;
+ALTER TABLE reviews
+    ADD CONSTRAINT fk_reviews_menu FOREIGN KEY (menu_id) REFERENCES menus (id) ON DELETE CASCADE NOT VALID;
+
+-- availability_schedules and price_rules store 0 for "no menu" or "no category", and
+-- email_outbox rows must outlive their order, so those columns get no foreign keys.
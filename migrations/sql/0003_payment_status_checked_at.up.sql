@@ -0,0 +1,3 @@
+-- When Midtrans last reported the payment status, so reads within the refresh interval
+-- are served from the database
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS status_checked_at timestamptz;
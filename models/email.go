@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+const (
+	EmailStatusPending = "pending"
+	EmailStatusFailed  = "failed" // Delivery failed, another attempt is scheduled
+	EmailStatusDead    = "dead"   // Gave up after the maximum number of attempts
+	EmailStatusSent    = "sent"
+)
+
+// Email kinds name the template an email was rendered from.
+// Invoice emails get the PDF receipt attached at send time.
+const (
+	EmailKindInvoice = "invoice"
+)
+
+// EmailOutbox is an email waiting to be delivered by the background dispatcher.
+// Rows are written in the same transaction as the change that triggers them.
+type EmailOutbox struct {
+	ID            int        `json:"id" gorm:"primary_key"`
+	Kind          string     `json:"kind"`
+	OrderID       int        `json:"order_id"`
+	Recipient     string     `json:"recipient"`
+	RecipientName string     `json:"recipient_name"`
+	Subject       string     `json:"subject"`
+	Body          string     `json:"-" gorm:"type:text"`
+	TextBody      string     `json:"-" gorm:"type:text"`
+	Status        string     `json:"status" gorm:"index"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+func (EmailOutbox) TableName() string {
+	return "email_outbox"
+}
+
+// EmailTemplate is a staff-edited version of a built-in email template. Saving creates a
+// new version, the highest version for a name and language is the one in use.
+type EmailTemplate struct {
+	ID        int       `json:"id" gorm:"primary_key"`
+	Name      string    `json:"name" gorm:"unique_index:idx_email_template_version"`
+	Language  string    `json:"language" gorm:"unique_index:idx_email_template_version"`
+	Version   int       `json:"version" gorm:"unique_index:idx_email_template_version"`
+	Subject   string    `json:"subject"`
+	HTML      string    `json:"html" gorm:"type:text"`
+	Text      string    `json:"text" gorm:"type:text"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EmailTemplateSummary describes one template and the version in use per language.
+// A version of 0 means the built-in template is used.
+type EmailTemplateSummary struct {
+	Name           string         `json:"name"`
+	ActiveVersions map[string]int `json:"active_versions"`
+}
+
+// EmailTemplateRequest is the body for saving a new template version.
+type EmailTemplateRequest struct {
+	Language string `json:"language" binding:"required,oneof=id en"`
+	Subject  string `json:"subject" binding:"required"`
+	HTML     string `json:"html" binding:"required"`
+	Text     string `json:"text"`
+}
+
+// EmailTemplatePreviewRequest previews a draft, or the template in use when no draft is given.
+// The preview uses order OrderID when set, otherwise a sample order.
+type EmailTemplatePreviewRequest struct {
+	Language string `json:"language" binding:"omitempty,oneof=id en"`
+	Subject  string `json:"subject"`
+	HTML     string `json:"html"`
+	Text     string `json:"text"`
+	OrderID  int    `json:"order_id"`
+}
@@ -2,33 +2,32 @@ package models
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/dimassfeb-09/pestapasta-be/utils"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres"
 )
 
-// InitializeDB initializes the database connection
-func InitializeDB() (*gorm.DB, error) {
-
-	env := utils.GetENV()
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s", env.DBHost, env.DBUser, env.DBPassword, env.DBName, env.DBPort, env.SSLMode)
-
-	db, err := gorm.Open("postgres", dsn)
+// InitializeDB initializes the database connection. The schema is managed by the
+// migrations package, run `migrate up` before starting a new version.
+func InitializeDB(cfg utils.Database) (*gorm.DB, error) {
+	db, err := gorm.Open("postgres", cfg.DSN())
 	if err != nil {
-		log.Fatal(err)
-		log.Fatal("failed to connect to the database")
 		return nil, err
 	}
+	db.SetLogger(gormLogger{})
 
-	if err := db.AutoMigrate(&User{}, &Category{}, &Menu{}, &Order{}, &OrderDetail{}, &Payment{}, &PaymentMethod{}).Error; err != nil {
-		log.Fatal("failed to migrate the database")
-		return nil, err
-	}
+	return db, nil
+}
 
-	fmt.Println("Successfully connected to the database")
+// gormLogger sends the errors gorm reports to slog instead of stdout.
+type gormLogger struct{}
 
-	return db, nil
+func (gormLogger) Print(values ...interface{}) {
+	if len(values) > 2 && values[0] == "log" {
+		slog.Error("Database error", "source", values[1], "error", fmt.Sprint(values[2:]...))
+		return
+	}
+	slog.Debug("Database", "detail", fmt.Sprint(values...))
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type User struct {
 	ID       int     `json:"id" gorm:"primary_key"` // Primary key di tabel users
 	Name     string  `json:"name"`
@@ -8,43 +10,158 @@ type User struct {
 	Orders   []Order `gorm:"foreignKey:UserID"`
 }
 
+// LoginRequest is the body staff log in with.
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
 // Category represents a food category.
 type Category struct {
 	ID           int    `json:"id" gorm:"primary_key"`
-	CategoryName string `json:"category_name" binding:"required"`
-	Description  string `json:"description" binding:"required"`
+	CategoryName string `json:"category_name" binding:"required,max=100"`
+	Description  string `json:"description" binding:"required,max=500"`
+}
+
+// CategoryPatch is a partial category update, nil fields are left unchanged.
+type CategoryPatch struct {
+	CategoryName *string `json:"category_name" binding:"omitempty,min=1,max=100"`
+	Description  *string `json:"description" binding:"omitempty,min=1,max=500"`
+}
+
+// Apply copies the fields set in p to category.
+func (p CategoryPatch) Apply(category *Category) {
+	if p.CategoryName != nil {
+		category.CategoryName = *p.CategoryName
+	}
+	if p.Description != nil {
+		category.Description = *p.Description
+	}
 }
 
 // PastaMenu represents a pasta menu item.
 type Menu struct {
-	ID          int     `json:"id" gorm:"primaryKey"`
-	Name        string  `json:"name" validate:"required"`
-	Price       float64 `json:"price" validate:"required"`
-	Description string  `json:"description" validate:"required"`
-	CategoryID  int     `json:"category_id" validate:"required"`
-	ImageURL    string  `json:"image_url" validate:"required"`
-	Rating      int     `json:"rating"`
-	IsAvailable bool    `gorm:"type:boolean; column:is_available" json:"is_available" validate:"required"`
+	ID            int     `json:"id" gorm:"primaryKey"`
+	Name          string  `json:"name" binding:"required,max=100"`
+	Price         float64 `json:"price" binding:"required,gt=0"`
+	Description   string  `json:"description" binding:"required,max=1000"`
+	CategoryID    int     `json:"category_id" binding:"required,gt=0"` // Must exist, checked by MenuService
+	ImageURL      string  `json:"image_url" binding:"required,url,max=2048"`
+	Rating        int     `json:"rating"` // Rounded RatingAverage, kept for older clients
+	RatingAverage float64 `json:"rating_average"`
+	RatingCount   int     `json:"rating_count"`
+	IsAvailable   bool    `gorm:"type:boolean; column:is_available" json:"is_available"`
+
+	// Computed from availability schedules and price rules, not stored
+	AvailableNow   bool    `json:"available_now" gorm:"-"`
+	EffectivePrice float64 `json:"effective_price" gorm:"-"`
+}
+
+// MenuRequest is the body a menu is created or replaced with. IsAvailable is required
+// so a replace that leaves it out cannot take the menu off sale.
+type MenuRequest struct {
+	Name        string  `json:"name" binding:"required,max=100"`
+	Price       float64 `json:"price" binding:"required,gt=0"`
+	Description string  `json:"description" binding:"required,max=1000"`
+	CategoryID  int     `json:"category_id" binding:"required,gt=0"`
+	ImageURL    string  `json:"image_url" binding:"required,url,max=2048"`
+	IsAvailable *bool   `json:"is_available" binding:"required"`
+}
+
+// Menu returns the menu the request describes.
+func (r MenuRequest) Menu() Menu {
+	return Menu{
+		Name:        r.Name,
+		Price:       r.Price,
+		Description: r.Description,
+		CategoryID:  r.CategoryID,
+		ImageURL:    r.ImageURL,
+		IsAvailable: r.IsAvailable != nil && *r.IsAvailable,
+	}
+}
+
+// MenuPatch is a partial menu update, nil fields are left unchanged.
+type MenuPatch struct {
+	Name        *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Price       *float64 `json:"price" binding:"omitempty,gt=0"`
+	Description *string  `json:"description" binding:"omitempty,min=1,max=1000"`
+	CategoryID  *int     `json:"category_id" binding:"omitempty,gt=0"`
+	ImageURL    *string  `json:"image_url" binding:"omitempty,url,max=2048"`
+	IsAvailable *bool    `json:"is_available"`
+}
+
+// Apply copies the fields set in p to menu.
+func (p MenuPatch) Apply(menu *Menu) {
+	if p.Name != nil {
+		menu.Name = *p.Name
+	}
+	if p.Price != nil {
+		menu.Price = *p.Price
+	}
+	if p.Description != nil {
+		menu.Description = *p.Description
+	}
+	if p.CategoryID != nil {
+		menu.CategoryID = *p.CategoryID
+	}
+	if p.ImageURL != nil {
+		menu.ImageURL = *p.ImageURL
+	}
+	if p.IsAvailable != nil {
+		menu.IsAvailable = *p.IsAvailable
+	}
 }
 
 // Order represents an order placed by a customer.
 type Order struct {
 	ID           int           `json:"id" gorm:"primary_key"` // Primary key di tabel orders
-	OrderDate    string        `json:"order_date"`
+	OrderDate    string        `json:"order_date"`            // UTC, "2006-01-02 15:04:05"
 	Email        string        `json:"email"`
 	Name         string        `json:"name"`
 	TotalPrice   float64       `json:"total_price"`
+	Discount     float64       `json:"discount"` // Price rule savings already deducted from TotalPrice
+	Tax          float64       `json:"tax"`      // Charged on top of TotalPrice
 	OrderStatus  string        `json:"order_status"`
+	Language     string        `json:"language"` // Customer preference for emails and receipts, see i18n
 	Payment      Payment       `json:"payments" gorm:"foreignKey:OrderID"`
 	OrderDetails []OrderDetail `json:"order_details" gorm:"foreignKey:OrderID"`
 }
 
+// TaxRate is the tax charged on top of the order subtotal.
+const TaxRate = 0.1
+
+// Fulfilment statuses are set by staff once an order is paid.
+const (
+	OrderStatusReadyForPickup = "ready_for_pickup"
+	OrderStatusCompleted      = "completed"
+)
+
+// PaidOrderStatuses lists the order statuses that mean the customer has paid.
+var PaidOrderStatuses = []string{"success", "captured", OrderStatusReadyForPickup, OrderStatusCompleted}
+
+// OrderStatusRequest is the body staff move an order to another status with.
+type OrderStatusRequest struct {
+	OrderStatus string `json:"order_status" binding:"required,oneof=success ready_for_pickup completed canceled refunded"`
+}
+
+// IsPaidStatus reports whether an order with status has been paid.
+func IsPaidStatus(status string) bool {
+	for _, paid := range PaidOrderStatuses {
+		if status == paid {
+			return true
+		}
+	}
+	return false
+}
+
 // OrderDetail represents details of a single pasta item in an order.
 type OrderDetail struct {
 	ID            int     `json:"id" gorm:"primary_key"`
 	OrderID       int     `json:"order_id"`
 	MenuID        int     `json:"menu_id"`
 	Quantity      int     `json:"quantity"`
+	UnitPrice     float64 `json:"unit_price"` // Price charged per item after price rules
+	Discount      float64 `json:"discount"`   // Price rule savings on this line
 	SubtotalPrice float64 `json:"subtotal_price"`
 	Notes         string  `json:"notes"`
 	Menu          Menu    `json:"menu_detail" gorm:"foreignKey:MenuID"`
@@ -61,21 +178,29 @@ type Payment struct {
 	TransactionCode      string `json:"transaction_code"`                 // Nullable field
 	PaymentAccountName   string `json:"payment_account_name,omitempty"`   // Nullable field
 	PaymentQRCodeURL     string `json:"payment_qr_code_url,omitempty"`    // Nullable field
+	PaymentQRString      string `json:"-" gorm:"type:text"`               // Nullable field, raw QRIS payload for receipts
 	PaymentCreateDate    string `json:"payment_create_date,omitempty"`    // Nullable field
 	PaymentExpiredDate   string `json:"payment_expired_date,omitempty"`   // Nullable field
 	PaymentTransactionID string `json:"payment_transaction_id,omitempty"` // Nullable field
+
+	// StatusCheckedAt is when Midtrans last reported PaymentStatus, nil until the first check
+	StatusCheckedAt *time.Time `json:"status_checked_at,omitempty"`
 }
 
 // CheckoutRequest represents the incoming request for checkout.
 type CheckoutRequest struct {
-	Name            string `json:"name"`
-	Email           string `json:"email"`
-	PaymentMethodID int    `json:"payment_method_id"`
-	Products        []struct {
-		ID       int    `json:"id"`
-		Quantity int    `json:"quantity"`
-		Notes    string `json:"notes"`
-	} `json:"products"`
+	Name            string         `json:"name" binding:"required,max=100"`
+	Email           string         `json:"email" binding:"required,email,max=254"`
+	PaymentMethodID int            `json:"payment_method_id" binding:"required,gt=0"`
+	Language        string         `json:"language" binding:"max=35"`                               // Optional, defaults to the Accept-Language of the request
+	Products        []CheckoutItem `json:"products" binding:"required,min=1,max=50,unique=ID,dive"` // One line per menu, quantities are not merged
+}
+
+// CheckoutItem is one menu and quantity in a CheckoutRequest.
+type CheckoutItem struct {
+	ID       int    `json:"id" binding:"required,gt=0"`
+	Quantity int    `json:"quantity" binding:"required,gt=0,max=99"`
+	Notes    string `json:"notes" binding:"max=255"`
 }
 
 type PaymentDetails struct {
@@ -117,9 +242,3 @@ type ResponseSuccessWithData struct {
 	Code    int    `json:"code"`
 	Data    any    `json:"data"`
 }
-
-type ResponseError struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
-}
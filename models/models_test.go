@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestCheckoutRequestBinding(t *testing.T) {
+	valid := func() CheckoutRequest {
+		return CheckoutRequest{
+			Name:            "Budi",
+			Email:           "budi@example.com",
+			PaymentMethodID: 1,
+			Products:        []CheckoutItem{{ID: 1, Quantity: 2}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *CheckoutRequest)
+		valid  bool
+	}{
+		{"valid", func(r *CheckoutRequest) {}, true},
+		{"invalid email", func(r *CheckoutRequest) { r.Email = "budi" }, false},
+		{"empty cart", func(r *CheckoutRequest) { r.Products = nil }, false},
+		{"negative product ID", func(r *CheckoutRequest) { r.Products[0].ID = -1 }, false},
+		{"too many of one product", func(r *CheckoutRequest) { r.Products[0].Quantity = 100 }, false},
+		{"product split across lines", func(r *CheckoutRequest) {
+			r.Products = []CheckoutItem{{ID: 1, Quantity: 2}, {ID: 2, Quantity: 1}, {ID: 1, Quantity: 3}}
+		}, false},
+		{"several products", func(r *CheckoutRequest) { r.Products = append(r.Products, CheckoutItem{ID: 2, Quantity: 1}) }, true},
+		{"missing payment method", func(r *CheckoutRequest) { r.PaymentMethodID = 0 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid()
+			tt.modify(&request)
+			if err := binding.Validator.ValidateStruct(request); (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid=%v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestMenuRequestBinding(t *testing.T) {
+	available := false
+	request := MenuRequest{
+		Name:        "Carbonara",
+		Price:       50000,
+		Description: "Creamy",
+		CategoryID:  1,
+		ImageURL:    "https://example.com/carbonara.jpg",
+	}
+	if err := binding.Validator.ValidateStruct(request); err == nil {
+		t.Errorf("request without is_available accepted")
+	}
+	request.IsAvailable = &available
+	if err := binding.Validator.ValidateStruct(request); err != nil {
+		t.Errorf("explicitly unavailable menu rejected: %v", err)
+	}
+	if menu := request.Menu(); menu.IsAvailable || menu.Name != "Carbonara" || menu.ImageURL != request.ImageURL {
+		t.Errorf("got %+v", menu)
+	}
+}
+
+func TestMenuPatchBinding(t *testing.T) {
+	price, empty := 0.0, ""
+	if err := binding.Validator.ValidateStruct(MenuPatch{}); err != nil {
+		t.Errorf("empty patch rejected: %v", err)
+	}
+	if err := binding.Validator.ValidateStruct(MenuPatch{Price: &price}); err == nil {
+		t.Errorf("patch setting price to 0 accepted")
+	}
+	if err := binding.Validator.ValidateStruct(MenuPatch{Name: &empty}); err == nil {
+		t.Errorf("patch clearing the name accepted")
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+// SalesFigures are the amounts every report row carries.
+// NetSales is GrossSales minus Discounts and Refunds; Tax is reported separately.
+type SalesFigures struct {
+	Orders            int     `json:"orders"`
+	Items             int     `json:"items"`
+	GrossSales        float64 `json:"gross_sales"`
+	Discounts         float64 `json:"discounts"`
+	Refunds           float64 `json:"refunds"`
+	NetSales          float64 `json:"net_sales"`
+	Tax               float64 `json:"tax"`
+	AverageOrderValue float64 `json:"average_order_value"`
+}
+
+// SalesReportRow is one row of a report grouped by period, menu, category or payment method.
+// Only the key fields of the requested grouping are set.
+type SalesReportRow struct {
+	Period        string `json:"period,omitempty"`
+	MenuID        int    `json:"menu_id,omitempty"`
+	MenuName      string `json:"menu_name,omitempty"`
+	CategoryID    int    `json:"category_id,omitempty"`
+	CategoryName  string `json:"category_name,omitempty"`
+	PaymentMethod string `json:"payment_method,omitempty"`
+	SalesFigures
+}
+
+// SalesReport wraps report rows with the parameters they were computed for.
+type SalesReport struct {
+	From     string           `json:"from"`
+	To       string           `json:"to"`
+	Timezone string           `json:"timezone"`
+	GroupBy  string           `json:"group_by"`
+	Rows     []SalesReportRow `json:"rows"`
+	Total    SalesFigures     `json:"total"`
+}
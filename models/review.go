@@ -0,0 +1,44 @@
+package models
+
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusHidden    = "hidden"
+)
+
+// Review is a customer's rating of a single order line, left after the order is paid.
+type Review struct {
+	ID            int    `json:"id" gorm:"primary_key"`
+	OrderID       int    `json:"order_id"`
+	OrderDetailID int    `json:"order_detail_id" gorm:"unique_index"`
+	MenuID        int    `json:"menu_id" gorm:"index"`
+	Name          string `json:"name"`
+	Rating        int    `json:"rating"`
+	Comment       string `json:"comment" gorm:"type:text"`
+	Status        string `json:"status"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// PublicReview is a published review as customers see it, without the order it was left on.
+type PublicReview struct {
+	ID        int    `json:"id"`
+	MenuID    int    `json:"menu_id"`
+	Name      string `json:"name"`
+	Rating    int    `json:"rating"`
+	Comment   string `json:"comment"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ReviewRequest is submitted by a customer, who proves ownership of the order
+// with its transaction code and the email used at checkout.
+type ReviewRequest struct {
+	TransactionCode string `json:"transaction_code" binding:"required"`
+	Email           string `json:"email" binding:"required"`
+	OrderDetailID   int    `json:"order_detail_id" binding:"required"`
+	Rating          int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment         string `json:"comment" binding:"max=1000"`
+}
+
+// ReviewStatusRequest is the body staff publish or hide a review with.
+type ReviewStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=published hidden"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// ScheduleWindow describes a recurring time window evaluated in Asia/Jakarta.
+// Empty fields are unbounded, so a zero ScheduleWindow matches any moment.
+type ScheduleWindow struct {
+	DaysOfWeek string `json:"days_of_week"` // Comma separated, 0 = Sunday ... 6 = Saturday
+	StartTime  string `json:"start_time"`   // HH:MM
+	EndTime    string `json:"end_time"`     // HH:MM, may be earlier than StartTime to cross midnight
+	StartDate  string `json:"start_date"`   // YYYY-MM-DD, first day of a seasonal window
+	EndDate    string `json:"end_date"`     // YYYY-MM-DD, last day of a seasonal window
+}
+
+// AvailabilitySchedule limits when a menu, or every menu in a category, can be ordered.
+// Exactly one of MenuID or CategoryID is set.
+type AvailabilitySchedule struct {
+	ID         int `json:"id" gorm:"primary_key"`
+	MenuID     int `json:"menu_id"`
+	CategoryID int `json:"category_id"`
+	ScheduleWindow
+}
+
+// PriceRule adjusts the price of a menu or category during a window (e.g. happy hour).
+// FixedPrice takes precedence over DiscountPercent when both are set.
+type PriceRule struct {
+	ID              int     `json:"id" gorm:"primary_key"`
+	Name            string  `json:"name"`
+	MenuID          int     `json:"menu_id"`
+	CategoryID      int     `json:"category_id"`
+	DiscountPercent float64 `json:"discount_percent"`
+	FixedPrice      float64 `json:"fixed_price"`
+	ScheduleWindow
+}
@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+)
+
+const jsonContent = "application/json"
+
+// componentNames renames schemas whose Go name means little outside their package.
+var componentNames = map[reflect.Type]string{
+	reflect.TypeOf(apperror.Response{}): "Error",
+	reflect.TypeOf(apperror.Body{}):     "ErrorBody",
+}
+
+// Group is a set of routes mounted under Prefix.
+type Group struct {
+	Prefix     string
+	Routes     []Route
+	Deprecated bool // Kept for old clients, documented as deprecated
+}
+
+// Build returns the document describing the routes of groups.
+func Build(version string, groups ...Group) Document {
+	s := newSchemas()
+	for t, name := range componentNames {
+		s.names[t] = name
+	}
+	for t, name := range componentNames {
+		s.components[name] = s.object(t)
+	}
+	errorResponse := Response{
+		Description: "Error, see error.code",
+		Content:     map[string]MediaType{jsonContent: {Schema: s.of(apperror.Response{})}},
+	}
+
+	doc := Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Pesta Pasta API",
+			Description: "Send Accept-Language: id or en to choose the language of messages.",
+			Version:     version,
+		},
+		Paths: map[string]PathItem{},
+	}
+	for _, group := range groups {
+		for _, route := range group.Routes {
+			route.Path = group.Prefix + route.Path
+			doc.addOperation(s, route, group.Deprecated, errorResponse)
+		}
+	}
+
+	doc.Components = Components{
+		Schemas: s.components,
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+	return doc
+}
+
+func (doc *Document) addOperation(s *schemas, route Route, deprecated bool, errorResponse Response) {
+	operation := &Operation{
+		Tags:       []string{route.Tag},
+		Summary:    route.Summary,
+		Parameters: parameters(route),
+		Responses:  map[string]Response{"200": {Description: "OK"}, "default": errorResponse},
+		Deprecated: deprecated,
+	}
+	if route.Auth {
+		operation.Security = []map[string][]string{{"bearerAuth": {}}}
+	}
+	if route.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: !route.OptionalBody,
+			Content:  map[string]MediaType{jsonContent: {Schema: s.of(route.Request)}},
+		}
+	}
+	switch {
+	case route.Content != "":
+		operation.Responses["200"] = Response{Description: "OK", Content: map[string]MediaType{
+			route.Content: {Schema: &Schema{Type: "string", Format: "binary"}},
+		}}
+	case route.Response != nil:
+		operation.Responses["200"] = Response{Description: "OK", Content: map[string]MediaType{
+			jsonContent: {Schema: s.of(route.Response)},
+		}}
+	}
+
+	path := Path(route.Path)
+	if doc.Paths[path] == nil {
+		doc.Paths[path] = PathItem{}
+	}
+	doc.Paths[path][strings.ToLower(route.Method)] = operation
+}
+
+// Path converts a gin path to an OpenAPI one: /menus/:id becomes /menus/{id}.
+func Path(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// parameters returns the path parameters of route, numeric IDs unless route.Params
+// describes them, followed by the parameters in route.Params.
+func parameters(route Route) []Parameter {
+	var params []Parameter
+	for _, segment := range strings.Split(route.Path, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := segment[1:]
+		if described(route.Params, name) {
+			continue
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "integer"}})
+	}
+	return append(params, route.Params...)
+}
+
+func described(params []Parameter, pathParam string) bool {
+	for _, param := range params {
+		if param.In == "path" && param.Name == pathParam {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package openapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func testDocument() Document {
+	return Build("test", Group{Routes: SystemRoutes}, Group{Prefix: "/api/v1", Routes: Routes})
+}
+
+func TestSchemaFromBindingTags(t *testing.T) {
+	components := testDocument().Components.Schemas
+
+	checkout := components["CheckoutRequest"]
+	if checkout == nil {
+		t.Fatalf("CheckoutRequest schema missing")
+	}
+	if got := strings.Join(checkout.Required, ","); got != "name,email,payment_method_id,products" {
+		t.Errorf("got required %s", got)
+	}
+	if checkout.Properties["email"].Format != "email" {
+		t.Errorf("email format not documented")
+	}
+	products := checkout.Properties["products"]
+	if products.MinItems == nil || *products.MinItems != 1 || products.Items.Ref != "#/components/schemas/CheckoutItem" {
+		t.Errorf("got products %+v, want at least one CheckoutItem", products)
+	}
+	quantity := components["CheckoutItem"].Properties["quantity"]
+	if quantity.Minimum == nil || *quantity.Minimum != 0 || !quantity.ExclusiveMinimum || *quantity.Maximum != 99 {
+		t.Errorf("got quantity %+v, want 0 < quantity <= 99", quantity)
+	}
+
+	// Embedded structs are flattened like encoding/json does
+	if components["AvailabilitySchedule"].Properties["start_time"] == nil {
+		t.Errorf("ScheduleWindow fields missing from AvailabilitySchedule")
+	}
+	if components["MenuPatch"].Properties["price"].Nullable != true {
+		t.Errorf("patch fields must be nullable")
+	}
+}
+
+func TestSpecReferencesResolve(t *testing.T) {
+	doc := testDocument()
+	body, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, match := range regexp.MustCompile(`"#/components/schemas/([^"]+)"`).FindAllSubmatch(body, -1) {
+		if doc.Components.Schemas[string(match[1])] == nil {
+			t.Errorf("$ref to undefined schema %s", match[1])
+		}
+	}
+	if doc.Paths["/api/v1/menus/{id}"]["patch"].Security == nil {
+		t.Errorf("staff routes must require the bearer token")
+	}
+}
@@ -0,0 +1,174 @@
+package openapi
+
+import (
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+)
+
+// Route documents one endpoint.
+type Route struct {
+	Method       string
+	Path         string // gin syntax, e.g. /menus/:id
+	Tag          string
+	Summary      string
+	Auth         bool        // Requires a staff JWT
+	Params       []Parameter // Query parameters, and path parameters that are not numeric IDs
+	Request      interface{} // Zero value of the JSON body, nil when there is none
+	OptionalBody bool
+	Response     interface{} // Zero value of the JSON 200 body
+	Content      string      // Content type of the 200 body when it is not JSON
+}
+
+// Response bodies handlers build with gin.H, mirrored here so they can be described.
+type (
+	healthResponse struct {
+		Status string `json:"status"`
+	}
+	readinessResponse struct {
+		Ready  bool              `json:"ready"`
+		Checks map[string]string `json:"checks"` // "ok" or what is wrong, per dependency
+	}
+	loginResponse struct {
+		Message string `json:"message"`
+		Token   string `json:"token"`
+	}
+	checkoutResult struct {
+		OrderID         int    `json:"order_id"`
+		TransactionCode string `json:"transaction_code"`
+	}
+	orderStatusResponse struct {
+		Message     string `json:"message"`
+		OrderStatus string `json:"order_status"`
+	}
+	emailTemplateVersions struct {
+		Name     string                 `json:"name"`
+		Builtin  helpers.TemplateSet    `json:"builtin"`
+		Versions []models.EmailTemplate `json:"versions"`
+	}
+	renderedEmail struct {
+		Subject string `json:"subject"`
+		HTML    string `json:"html"`
+		Text    string `json:"text"`
+	}
+)
+
+// dataResponse is models.ResponseSuccessWithData with the type of Data spelled out.
+type dataResponse[T any] struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Data    T      `json:"data"`
+}
+
+func query(name, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+func queryInt(name, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "integer"}}
+}
+
+var (
+	paginationParams = []Parameter{
+		queryInt("limit", "Page size, default 20, at most 100"),
+		queryInt("offset", "Rows to skip, default 0"),
+	}
+	orderFilterParams = []Parameter{
+		query("status", "Order status"),
+		query("email", "Customer email"),
+		query("payment_method", "Payment method code"),
+		query("from", "First order date, YYYY-MM-DD"),
+		query("to", "Last order date, YYYY-MM-DD, inclusive"),
+	}
+	reportParams = []Parameter{
+		query("from", "First day, YYYY-MM-DD, defaults to 29 days before to"),
+		query("to", "Last day, YYYY-MM-DD, inclusive, defaults to today"),
+		query("tz", "IANA timezone the days are counted in, default Asia/Jakarta"),
+	}
+	salesReportParams = append([]Parameter{
+		{Name: "group_by", In: "query", Schema: &Schema{Type: "string", Enum: []string{"day", "week", "month"}}},
+	}, reportParams...)
+	exportReportParams = append([]Parameter{
+		{Name: "group_by", In: "query", Schema: &Schema{Type: "string", Enum: []string{"day", "week", "month", "menu", "category", "payment_method"}}},
+	}, reportParams...)
+	templateName = Parameter{Name: "name", In: "path", Required: true, Description: "Template name, e.g. invoice", Schema: &Schema{Type: "string"}}
+)
+
+const (
+	contentCSV  = "text/csv"
+	contentXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	contentHTML = "text/html"
+)
+
+// SystemRoutes are the unversioned endpoints for the platform and monitoring.
+var SystemRoutes = []Route{
+	{Method: "GET", Path: "/healthz", Tag: "system", Summary: "Liveness probe", Response: healthResponse{}},
+	{Method: "GET", Path: "/readyz", Tag: "system", Summary: "Readiness probe, 503 while not ready", Response: readinessResponse{}},
+	{Method: "GET", Path: "/version", Tag: "system", Summary: "Version of the running build", Response: utils.BuildInfo{}},
+	{Method: "GET", Path: "/metrics", Tag: "system", Summary: "Prometheus metrics", Content: "text/plain"},
+}
+
+// Routes lists the endpoints of the API, relative to the version prefix.
+var Routes = []Route{
+	// Public
+	{Method: "GET", Path: "/openapi.json", Tag: "docs", Summary: "This document", Content: "application/json"},
+	{Method: "GET", Path: "/docs", Tag: "docs", Summary: "API documentation", Content: contentHTML},
+	{Method: "POST", Path: "/login", Tag: "auth", Summary: "Log in as staff and get a JWT", Request: models.LoginRequest{}, Response: loginResponse{}},
+	{Method: "POST", Path: "/checkout", Tag: "orders", Summary: "Place an order", Request: models.CheckoutRequest{}, Response: dataResponse[checkoutResult]{}},
+	{Method: "GET", Path: "/menus", Tag: "menus", Summary: "List menus", Params: []Parameter{query("category", "Only menus in the category with this name")}, Response: []models.Menu{}},
+	{Method: "GET", Path: "/menus/:id", Tag: "menus", Summary: "Get a menu", Response: models.Menu{}},
+	{Method: "GET", Path: "/menus/:id/reviews", Tag: "reviews", Summary: "List published reviews of a menu", Params: paginationParams, Response: []models.PublicReview{}},
+	{Method: "POST", Path: "/reviews", Tag: "reviews", Summary: "Review an item of a paid order", Request: models.ReviewRequest{}, Response: dataResponse[models.Review]{}},
+	{Method: "GET", Path: "/categories", Tag: "categories", Summary: "List categories", Response: []models.Category{}},
+	{Method: "GET", Path: "/categories/:id", Tag: "categories", Summary: "Get a category", Response: models.Category{}},
+	{Method: "GET", Path: "/payment_methods", Tag: "payments", Summary: "List payment methods", Response: []models.PaymentMethod{}},
+	{Method: "GET", Path: "/orders/:id/status", Tag: "orders", Summary: "Refresh and return the payment status of an order", Response: orderStatusResponse{}},
+	{Method: "GET", Path: "/orders/:id/receipt.pdf", Tag: "orders", Summary: "Download the receipt of an order", Content: "application/pdf", Params: []Parameter{
+		{Name: "id", In: "path", Required: true, Description: "Transaction code of the order", Schema: &Schema{Type: "string"}},
+	}},
+	{Method: "GET", Path: "/orders/:id", Tag: "orders", Summary: "Refresh the payment status of an order and return it", Response: models.Order{}},
+
+	// Staff
+	{Method: "GET", Path: "/orders", Tag: "orders", Summary: "List orders", Auth: true, Params: orderFilterParams, Response: []models.Order{}},
+	{Method: "PUT", Path: "/orders/:id/status", Tag: "orders", Summary: "Move an order to another status", Auth: true, Request: models.OrderStatusRequest{}, Response: models.ResponseSuccess{}},
+	{Method: "POST", Path: "/menus", Tag: "menus", Summary: "Create a menu", Auth: true, Request: models.MenuRequest{}, Response: models.ResponseSuccess{}},
+	{Method: "PUT", Path: "/menus/:id", Tag: "menus", Summary: "Replace a menu", Auth: true, Request: models.MenuRequest{}, Response: models.ResponseSuccess{}},
+	{Method: "PATCH", Path: "/menus/:id", Tag: "menus", Summary: "Change some fields of a menu", Auth: true, Request: models.MenuPatch{}, Response: models.ResponseSuccess{}},
+	{Method: "POST", Path: "/categories", Tag: "categories", Summary: "Create a category", Auth: true, Request: models.Category{}, Response: models.ResponseSuccess{}},
+	{Method: "PUT", Path: "/categories/:id", Tag: "categories", Summary: "Replace a category", Auth: true, Request: models.Category{}, Response: models.ResponseSuccess{}},
+	{Method: "PATCH", Path: "/categories/:id", Tag: "categories", Summary: "Change some fields of a category", Auth: true, Request: models.CategoryPatch{}, Response: models.ResponseSuccess{}},
+	{Method: "GET", Path: "/reports/sales", Tag: "reports", Summary: "Sales per day, week or month", Auth: true, Params: salesReportParams, Response: models.SalesReport{}},
+	{Method: "GET", Path: "/reports/menus", Tag: "reports", Summary: "Sales per menu", Auth: true, Params: reportParams, Response: models.SalesReport{}},
+	{Method: "GET", Path: "/reports/categories", Tag: "reports", Summary: "Sales per category", Auth: true, Params: reportParams, Response: models.SalesReport{}},
+	{Method: "GET", Path: "/reports/payment_methods", Tag: "reports", Summary: "Sales per payment method", Auth: true, Params: reportParams, Response: models.SalesReport{}},
+	{Method: "GET", Path: "/emails", Tag: "emails", Summary: "List outbox emails, failed and dead ones by default", Auth: true, Params: append([]Parameter{query("status", "pending, failed, dead or sent")}, paginationParams...), Response: []models.EmailOutbox{}},
+	{Method: "POST", Path: "/emails/:id/resend", Tag: "emails", Summary: "Retry a failed email", Auth: true, Response: models.ResponseSuccess{}},
+	{Method: "GET", Path: "/email_templates", Tag: "email templates", Summary: "List templates and the version in use", Auth: true, Response: []models.EmailTemplateSummary{}},
+	{Method: "GET", Path: "/email_templates/:name", Tag: "email templates", Summary: "List the stored versions of a template", Auth: true, Params: []Parameter{templateName, query("language", "id or en")}, Response: emailTemplateVersions{}},
+	{Method: "PUT", Path: "/email_templates/:name", Tag: "email templates", Summary: "Save a new version of a template", Auth: true, Params: []Parameter{templateName}, Request: models.EmailTemplateRequest{}, Response: dataResponse[models.EmailTemplate]{}},
+	{Method: "POST", Path: "/email_templates/:name/preview", Tag: "email templates", Summary: "Render a template or draft; format=html or text returns the body only", Auth: true, Params: []Parameter{
+		templateName, query("format", "html or text"), query("payment_method", "Payment method of the sample order, default qris"),
+	}, Request: models.EmailTemplatePreviewRequest{}, OptionalBody: true, Response: renderedEmail{}},
+	{Method: "GET", Path: "/exports/orders.csv", Tag: "exports", Summary: "Export order lines as CSV", Auth: true, Params: orderFilterParams, Content: contentCSV},
+	{Method: "GET", Path: "/exports/orders.xlsx", Tag: "exports", Summary: "Export order lines as XLSX", Auth: true, Params: orderFilterParams, Content: contentXLSX},
+	{Method: "GET", Path: "/exports/reports.csv", Tag: "exports", Summary: "Export a sales report as CSV", Auth: true, Params: exportReportParams, Content: contentCSV},
+	{Method: "GET", Path: "/exports/reports.xlsx", Tag: "exports", Summary: "Export a sales report as XLSX", Auth: true, Params: exportReportParams, Content: contentXLSX},
+	{Method: "GET", Path: "/reviews", Tag: "reviews", Summary: "List reviews", Auth: true, Params: append([]Parameter{
+		query("status", "published or hidden"), queryInt("menu_id", "Only reviews of this menu"),
+	}, paginationParams...), Response: []models.Review{}},
+	{Method: "PUT", Path: "/reviews/:id/status", Tag: "reviews", Summary: "Publish or hide a review", Auth: true, Request: models.ReviewStatusRequest{}, Response: models.ResponseSuccess{}},
+	{Method: "DELETE", Path: "/reviews/:id", Tag: "reviews", Summary: "Delete a review", Auth: true, Response: models.ResponseSuccess{}},
+	{Method: "GET", Path: "/schedules", Tag: "schedules", Summary: "List availability schedules", Auth: true, Response: []models.AvailabilitySchedule{}},
+	{Method: "POST", Path: "/schedules", Tag: "schedules", Summary: "Create an availability schedule", Auth: true, Request: models.AvailabilitySchedule{}, Response: dataResponse[models.AvailabilitySchedule]{}},
+	{Method: "PUT", Path: "/schedules/:id", Tag: "schedules", Summary: "Replace an availability schedule", Auth: true, Request: models.AvailabilitySchedule{}, Response: models.ResponseSuccess{}},
+	{Method: "DELETE", Path: "/schedules/:id", Tag: "schedules", Summary: "Delete an availability schedule", Auth: true, Response: models.ResponseSuccess{}},
+	{Method: "GET", Path: "/price_rules", Tag: "price rules", Summary: "List price rules", Auth: true, Response: []models.PriceRule{}},
+	{Method: "POST", Path: "/price_rules", Tag: "price rules", Summary: "Create a price rule", Auth: true, Request: models.PriceRule{}, Response: dataResponse[models.PriceRule]{}},
+	{Method: "PUT", Path: "/price_rules/:id", Tag: "price rules", Summary: "Replace a price rule", Auth: true, Request: models.PriceRule{}, Response: models.ResponseSuccess{}},
+	{Method: "DELETE", Path: "/price_rules/:id", Tag: "price rules", Summary: "Delete a price rule", Auth: true, Response: models.ResponseSuccess{}},
+
+	// Development only, registered when emails are captured instead of sent
+	{Method: "GET", Path: "/dev/emails", Tag: "dev", Summary: "List captured emails", Response: []helpers.CapturedMessage{}},
+	{Method: "GET", Path: "/dev/emails/:id", Tag: "dev", Summary: "Show a captured email; format=text for the text body", Params: []Parameter{query("format", "text")}, Content: contentHTML},
+}
@@ -0,0 +1,64 @@
+// Package ratelimit limits how often a client may call a route with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Store keeps the token buckets. MemoryStore serves a single instance, a shared backend
+// such as Redis lets several instances enforce one limit.
+type Store interface {
+	// Take removes a token from the bucket at key and returns zero, or how long the
+	// caller must wait for a token when the bucket is empty.
+	Take(ctx context.Context, key string, limit utils.Limit) (time.Duration, error)
+}
+
+// Limiter applies the configured limits to requests.
+type Limiter struct {
+	store  Store
+	config utils.RateLimit
+}
+
+func New(store Store, config utils.RateLimit) *Limiter {
+	return &Limiter{store: store, config: config}
+}
+
+// Limit returns the limit of route, e.g. "POST /checkout".
+func (l *Limiter) Limit(route string) utils.Limit {
+	if limit, ok := l.config.Routes[route]; ok {
+		return limit
+	}
+	return l.config.Default
+}
+
+// Middleware rejects requests over the limit of their route with 429 and a Retry-After
+// header. prefix is trimmed from route templates, so API versions mounted under
+// different prefixes share their buckets.
+func (l *Limiter) Middleware(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + strings.TrimPrefix(c.FullPath(), prefix)
+		wait, err := l.store.Take(c.Request.Context(), c.ClientIP()+" "+route, l.Limit(route))
+		if err != nil {
+			// An unreachable store must not take the API down with it
+			slog.WarnContext(c.Request.Context(), "rate limit store failed", "route", route, "error", err)
+			c.Next()
+			return
+		}
+		if wait > 0 {
+			seconds := int(math.Ceil(wait.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			apperror.Abort(c, apperror.RateLimited(seconds))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMemoryStoreRefills(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := utils.Limit{PerMinute: 6, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		if wait, _ := store.Take(context.Background(), "a", limit); wait != 0 {
+			t.Fatalf("request %d within the burst waited %s", i, wait)
+		}
+	}
+	if wait, _ := store.Take(context.Background(), "a", limit); wait != 10*time.Second {
+		t.Errorf("got wait %s, want one token every 10s", wait)
+	}
+	if wait, _ := store.Take(context.Background(), "b", limit); wait != 0 {
+		t.Errorf("keys must not share a bucket")
+	}
+
+	now = now.Add(4 * time.Second)
+	if wait, _ := store.Take(context.Background(), "a", limit); wait.Round(time.Millisecond) != 6*time.Second {
+		t.Errorf("got wait %s after a partial refill, want 6s", wait)
+	}
+	now = now.Add(6 * time.Second)
+	if wait, _ := store.Take(context.Background(), "a", limit); wait != 0 {
+		t.Errorf("refilled token not granted, waited %s", wait)
+	}
+
+	now = now.Add(time.Hour)
+	store.Take(context.Background(), "c", limit)
+	if _, ok := store.buckets["a"]; ok {
+		t.Errorf("full buckets should be forgotten")
+	}
+}
+
+func TestMiddlewareRejectsWithRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := New(NewMemoryStore(), utils.RateLimit{
+		Default: utils.Limit{PerMinute: 60, Burst: 5},
+		Routes:  map[string]utils.Limit{"POST /checkout": {PerMinute: 1, Burst: 1}},
+	})
+	r := gin.New()
+	r.Use(apperror.Middleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.POST("/checkout", limiter.Middleware("/api/v1"), ok)
+	r.POST("/api/v1/checkout", limiter.Middleware("/api/v1"), ok)
+	r.GET("/menus", limiter.Middleware("/api/v1"), ok)
+
+	statuses := []int{}
+	var last *httptest.ResponseRecorder
+	for _, path := range []string{"/checkout", "/api/v1/checkout"} {
+		last = httptest.NewRecorder()
+		r.ServeHTTP(last, httptest.NewRequest(http.MethodPost, path, nil))
+		statuses = append(statuses, last.Code)
+	}
+	if statuses[0] != http.StatusOK || statuses[1] != http.StatusTooManyRequests {
+		t.Fatalf("got statuses %v, want the versioned path to share the legacy bucket", statuses)
+	}
+	if got := last.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("got Retry-After %q, want 60", got)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/menus", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("other routes must keep their own bucket, got %d", w.Code)
+	}
+}
@@ -0,0 +1,255 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/tracing"
+	"github.com/jinzhu/gorm"
+)
+
+// GormStore stores data in Postgres through gorm. gorm v1 does not take a context, so
+// ctx is only checked before a transaction starts and otherwise carries the trace.
+type GormStore struct {
+	db *gorm.DB
+}
+
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+func (s *GormStore) Users() UserRepository                   { return gormUsers{s.db} }
+func (s *GormStore) Categories() CategoryRepository          { return gormCategories{s.db} }
+func (s *GormStore) Menus() MenuRepository                   { return gormMenus{s.db} }
+func (s *GormStore) Orders() OrderRepository                 { return gormOrders{s.db} }
+func (s *GormStore) Payments() PaymentRepository             { return gormPayments{s.db} }
+func (s *GormStore) PaymentMethods() PaymentMethodRepository { return gormPaymentMethods{s.db} }
+func (s *GormStore) Schedules() ScheduleRepository           { return gormSchedules{s.db} }
+func (s *GormStore) Emails() EmailRepository                 { return gormEmails{s.db} }
+
+func (s *GormStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	if err := fn(&GormStore{db: tx}); err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// notFound maps gorm's not found error to ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ApplyOrderFilter narrows a query on the orders table by filter.
+func ApplyOrderFilter(query *gorm.DB, filter OrderFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("orders.order_status = ?", filter.Status)
+	}
+	if filter.Email != "" {
+		query = query.Where("LOWER(orders.email) = ?", strings.ToLower(filter.Email))
+	}
+	if filter.PaymentMethod != "" {
+		query = query.Where("orders.id IN (SELECT order_id FROM payments WHERE LOWER(payment_method) = ?)", strings.ToLower(filter.PaymentMethod))
+	}
+	if filter.From != "" {
+		query = query.Where("orders.order_date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		query = query.Where("orders.order_date < ?", filter.before())
+	}
+	return query
+}
+
+type gormUsers struct{ db *gorm.DB }
+
+func (r gormUsers) FindByUsername(ctx context.Context, username string) (models.User, error) {
+	var user models.User
+	err := tracing.WithContext(r.db, ctx).Where("username = ?", username).First(&user).Error
+	return user, notFound(err)
+}
+
+type gormCategories struct{ db *gorm.DB }
+
+func (r gormCategories) List(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	err := tracing.WithContext(r.db, ctx).Find(&categories).Error
+	return categories, err
+}
+
+func (r gormCategories) FindByID(ctx context.Context, id int) (models.Category, error) {
+	var category models.Category
+	err := tracing.WithContext(r.db, ctx).Where("id = ?", id).First(&category).Error
+	return category, notFound(err)
+}
+
+func (r gormCategories) Create(ctx context.Context, category *models.Category) error {
+	return tracing.WithContext(r.db, ctx).Create(category).Error
+}
+
+func (r gormCategories) Update(ctx context.Context, id int, category models.Category) error {
+	return tracing.WithContext(r.db, ctx).Model(&models.Category{}).Where("id = ?", id).Updates(category).Error
+}
+
+type gormMenus struct{ db *gorm.DB }
+
+func (r gormMenus) List(ctx context.Context, category string) ([]models.Menu, error) {
+	query := tracing.WithContext(r.db, ctx)
+	if category != "" {
+		query = query.Joins("JOIN categories ON categories.id = menus.category_id").
+			Where("categories.category_name = ?", category)
+	}
+
+	var menus []models.Menu
+	err := query.Find(&menus).Error
+	return menus, err
+}
+
+func (r gormMenus) FindByID(ctx context.Context, id int) (models.Menu, error) {
+	var menu models.Menu
+	err := tracing.WithContext(r.db, ctx).Where("id = ?", id).First(&menu).Error
+	return menu, notFound(err)
+}
+
+func (r gormMenus) FindByIDs(ctx context.Context, ids []int) ([]models.Menu, error) {
+	var menus []models.Menu
+	err := tracing.WithContext(r.db, ctx).Where("id IN (?)", ids).Find(&menus).Error
+	return menus, err
+}
+
+func (r gormMenus) Create(ctx context.Context, menu *models.Menu) error {
+	return tracing.WithContext(r.db, ctx).Create(menu).Error
+}
+
+func (r gormMenus) Update(ctx context.Context, menu models.Menu) error {
+	return tracing.WithContext(r.db, ctx).Model(&models.Menu{}).Where("id = ?", menu.ID).Updates(map[string]interface{}{
+		"name":         menu.Name,
+		"price":        menu.Price,
+		"description":  menu.Description,
+		"category_id":  menu.CategoryID,
+		"image_url":    menu.ImageURL,
+		"is_available": menu.IsAvailable,
+	}).Error
+}
+
+type gormOrders struct{ db *gorm.DB }
+
+func (r gormOrders) preloaded(ctx context.Context) *gorm.DB {
+	return tracing.WithContext(r.db, ctx).Preload("Payment").Preload("OrderDetails").Preload("OrderDetails.Menu")
+}
+
+func (r gormOrders) List(ctx context.Context, filter OrderFilter) ([]models.Order, error) {
+	var orders []models.Order
+	err := ApplyOrderFilter(r.preloaded(ctx).Model(&models.Order{}), filter).Find(&orders).Error
+	return orders, err
+}
+
+func (r gormOrders) FindByID(ctx context.Context, id int) (models.Order, error) {
+	var order models.Order
+	err := r.preloaded(ctx).First(&order, "id = ?", id).Error
+	return order, notFound(err)
+}
+
+func (r gormOrders) FindByTransactionCode(ctx context.Context, transactionCode string) (models.Order, error) {
+	var order models.Order
+	err := r.preloaded(ctx).
+		Joins("JOIN payments ON payments.order_id = orders.id").
+		Where("payments.transaction_code = ?", transactionCode).
+		First(&order).Error
+	return order, notFound(err)
+}
+
+func (r gormOrders) Create(ctx context.Context, order *models.Order) error {
+	// Details are inserted below and the payment by PaymentRepository
+	db := tracing.WithContext(r.db, ctx).Set("gorm:save_associations", false)
+	if err := db.Create(order).Error; err != nil {
+		return err
+	}
+	for i := range order.OrderDetails {
+		order.OrderDetails[i].OrderID = order.ID
+		if err := db.Create(&order.OrderDetails[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r gormOrders) UpdateStatus(ctx context.Context, id int, status string) error {
+	return tracing.WithContext(r.db, ctx).Model(&models.Order{}).Where("id = ?", id).Update("order_status", status).Error
+}
+
+type gormPayments struct{ db *gorm.DB }
+
+func (r gormPayments) Create(ctx context.Context, payment *models.Payment) error {
+	return tracing.WithContext(r.db, ctx).Create(payment).Error
+}
+
+func (r gormPayments) UpdateStatusByOrderID(ctx context.Context, orderID int, status string) error {
+	return tracing.WithContext(r.db, ctx).Model(&models.Payment{}).Where("order_id = ?", orderID).Update("payment_status", status).Error
+}
+
+func (r gormPayments) RecordStatusCheck(ctx context.Context, orderID int, status string, checkedAt time.Time) error {
+	return tracing.WithContext(r.db, ctx).Model(&models.Payment{}).Where("order_id = ?", orderID).
+		Updates(map[string]interface{}{"payment_status": status, "status_checked_at": checkedAt}).Error
+}
+
+type gormPaymentMethods struct{ db *gorm.DB }
+
+func (r gormPaymentMethods) List(ctx context.Context) ([]models.PaymentMethod, error) {
+	var methods []models.PaymentMethod
+	err := tracing.WithContext(r.db, ctx).Find(&methods).Error
+	return methods, err
+}
+
+func (r gormPaymentMethods) FindByID(ctx context.Context, id int) (models.PaymentMethod, error) {
+	var method models.PaymentMethod
+	err := tracing.WithContext(r.db, ctx).Where("id = ?", id).First(&method).Error
+	return method, notFound(err)
+}
+
+type gormSchedules struct{ db *gorm.DB }
+
+func (r gormSchedules) ListSchedules(ctx context.Context) ([]models.AvailabilitySchedule, error) {
+	var schedules []models.AvailabilitySchedule
+	err := tracing.WithContext(r.db, ctx).Find(&schedules).Error
+	return schedules, err
+}
+
+func (r gormSchedules) ListPriceRules(ctx context.Context) ([]models.PriceRule, error) {
+	var rules []models.PriceRule
+	err := tracing.WithContext(r.db, ctx).Find(&rules).Error
+	return rules, err
+}
+
+type gormEmails struct{ db *gorm.DB }
+
+func (r gormEmails) ActiveTemplate(ctx context.Context, name, lang string) (*models.EmailTemplate, error) {
+	var stored models.EmailTemplate
+	err := tracing.WithContext(r.db, ctx).Where("name = ? AND language = ?", name, lang).Order("version DESC").First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (r gormEmails) CreateOutbox(ctx context.Context, email *models.EmailOutbox) error {
+	return tracing.WithContext(r.db, ctx).Create(email).Error
+}
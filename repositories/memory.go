@@ -0,0 +1,505 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/models"
+)
+
+// MemoryStore keeps data in maps so services can be tested without Postgres.
+// Transactions run one at a time and restore a snapshot when fn fails.
+type MemoryStore struct {
+	mu   sync.Mutex // Guards data
+	txMu sync.Mutex // Serializes transactions
+	data *memoryData
+}
+
+type memoryData struct {
+	lastID         int
+	users          map[int]models.User
+	categories     map[int]models.Category
+	menus          map[int]models.Menu
+	orders         map[int]models.Order
+	orderDetails   map[int]models.OrderDetail
+	payments       map[int]models.Payment
+	paymentMethods map[int]models.PaymentMethod
+	schedules      map[int]models.AvailabilitySchedule
+	priceRules     map[int]models.PriceRule
+	emailTemplates map[int]models.EmailTemplate
+	outbox         map[int]models.EmailOutbox
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: &memoryData{
+		users:          map[int]models.User{},
+		categories:     map[int]models.Category{},
+		menus:          map[int]models.Menu{},
+		orders:         map[int]models.Order{},
+		orderDetails:   map[int]models.OrderDetail{},
+		payments:       map[int]models.Payment{},
+		paymentMethods: map[int]models.PaymentMethod{},
+		schedules:      map[int]models.AvailabilitySchedule{},
+		priceRules:     map[int]models.PriceRule{},
+		emailTemplates: map[int]models.EmailTemplate{},
+		outbox:         map[int]models.EmailOutbox{},
+	}}
+}
+
+func (d *memoryData) clone() *memoryData {
+	return &memoryData{
+		lastID:         d.lastID,
+		users:          cloneMap(d.users),
+		categories:     cloneMap(d.categories),
+		menus:          cloneMap(d.menus),
+		orders:         cloneMap(d.orders),
+		orderDetails:   cloneMap(d.orderDetails),
+		payments:       cloneMap(d.payments),
+		paymentMethods: cloneMap(d.paymentMethods),
+		schedules:      cloneMap(d.schedules),
+		priceRules:     cloneMap(d.priceRules),
+		emailTemplates: cloneMap(d.emailTemplates),
+		outbox:         cloneMap(d.outbox),
+	}
+}
+
+func cloneMap[T any](m map[int]T) map[int]T {
+	clone := make(map[int]T, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
+
+// nextID returns the ID to use for a new record when id is zero. IDs are unique across
+// tables, which keeps tests from passing by accident with the wrong kind of ID.
+func (d *memoryData) nextID(id int) int {
+	if id == 0 {
+		d.lastID++
+		return d.lastID
+	}
+	if id > d.lastID {
+		d.lastID = id
+	}
+	return id
+}
+
+// Seed adds records, given as model values, assigning IDs to those without one.
+// Orders are stored without their Payment and OrderDetails, seed those separately.
+func (s *MemoryStore) Seed(records ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.data
+	for _, record := range records {
+		switch r := record.(type) {
+		case models.User:
+			r.ID = d.nextID(r.ID)
+			d.users[r.ID] = r
+		case models.Category:
+			r.ID = d.nextID(r.ID)
+			d.categories[r.ID] = r
+		case models.Menu:
+			r.ID = d.nextID(r.ID)
+			d.menus[r.ID] = r
+		case models.Order:
+			r.ID = d.nextID(r.ID)
+			r.Payment, r.OrderDetails = models.Payment{}, nil
+			d.orders[r.ID] = r
+		case models.OrderDetail:
+			r.ID = d.nextID(r.ID)
+			r.Menu = models.Menu{}
+			d.orderDetails[r.ID] = r
+		case models.Payment:
+			r.ID = d.nextID(r.ID)
+			d.payments[r.ID] = r
+		case models.PaymentMethod:
+			r.ID = d.nextID(r.ID)
+			d.paymentMethods[r.ID] = r
+		case models.AvailabilitySchedule:
+			r.ID = d.nextID(r.ID)
+			d.schedules[r.ID] = r
+		case models.PriceRule:
+			r.ID = d.nextID(r.ID)
+			d.priceRules[r.ID] = r
+		case models.EmailTemplate:
+			r.ID = d.nextID(r.ID)
+			d.emailTemplates[r.ID] = r
+		default:
+			panic(fmt.Sprintf("repositories: cannot seed %T", record))
+		}
+	}
+}
+
+// Outbox returns the queued emails ordered by ID.
+func (s *MemoryStore) Outbox() []models.EmailOutbox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.data.outbox, func(e models.EmailOutbox) int { return e.ID })
+}
+
+func sortedValues[T any](m map[int]T, id func(T) int) []T {
+	values := make([]T, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return id(values[i]) < id(values[j]) })
+	return values
+}
+
+func (s *MemoryStore) Users() UserRepository                   { return memoryUsers{s} }
+func (s *MemoryStore) Categories() CategoryRepository          { return memoryCategories{s} }
+func (s *MemoryStore) Menus() MenuRepository                   { return memoryMenus{s} }
+func (s *MemoryStore) Orders() OrderRepository                 { return memoryOrders{s} }
+func (s *MemoryStore) Payments() PaymentRepository             { return memoryPayments{s} }
+func (s *MemoryStore) PaymentMethods() PaymentMethodRepository { return memoryPaymentMethods{s} }
+func (s *MemoryStore) Schedules() ScheduleRepository           { return memorySchedules{s} }
+func (s *MemoryStore) Emails() EmailRepository                 { return memoryEmails{s} }
+
+func (s *MemoryStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	snapshot := s.data.clone()
+	s.mu.Unlock()
+
+	if err := fn(memoryTx{s}); err != nil {
+		s.mu.Lock()
+		s.data = snapshot
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// memoryTx is the Store handed to a transaction, nested transactions join the outer one.
+type memoryTx struct {
+	*MemoryStore
+}
+
+func (tx memoryTx) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return fn(tx)
+}
+
+// locked runs fn with the data locked.
+func (s *MemoryStore) locked(fn func(d *memoryData)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.data)
+}
+
+type memoryUsers struct{ s *MemoryStore }
+
+func (r memoryUsers) FindByUsername(ctx context.Context, username string) (user models.User, err error) {
+	err = ErrNotFound
+	r.s.locked(func(d *memoryData) {
+		for _, u := range d.users {
+			if u.Username == username {
+				user, err = u, nil
+				return
+			}
+		}
+	})
+	return user, err
+}
+
+type memoryCategories struct{ s *MemoryStore }
+
+func (r memoryCategories) List(ctx context.Context) (categories []models.Category, err error) {
+	r.s.locked(func(d *memoryData) {
+		categories = sortedValues(d.categories, func(c models.Category) int { return c.ID })
+	})
+	return categories, nil
+}
+
+func (r memoryCategories) FindByID(ctx context.Context, id int) (category models.Category, err error) {
+	r.s.locked(func(d *memoryData) {
+		var ok bool
+		if category, ok = d.categories[id]; !ok {
+			err = ErrNotFound
+		}
+	})
+	return category, err
+}
+
+func (r memoryCategories) Create(ctx context.Context, category *models.Category) error {
+	r.s.locked(func(d *memoryData) {
+		category.ID = d.nextID(category.ID)
+		d.categories[category.ID] = *category
+	})
+	return nil
+}
+
+func (r memoryCategories) Update(ctx context.Context, id int, category models.Category) error {
+	r.s.locked(func(d *memoryData) {
+		existing, ok := d.categories[id]
+		if !ok {
+			return
+		}
+		if category.CategoryName != "" {
+			existing.CategoryName = category.CategoryName
+		}
+		if category.Description != "" {
+			existing.Description = category.Description
+		}
+		d.categories[id] = existing
+	})
+	return nil
+}
+
+type memoryMenus struct{ s *MemoryStore }
+
+func (r memoryMenus) List(ctx context.Context, category string) (menus []models.Menu, err error) {
+	r.s.locked(func(d *memoryData) {
+		menus = []models.Menu{}
+		for _, menu := range sortedValues(d.menus, func(m models.Menu) int { return m.ID }) {
+			if category == "" || d.categories[menu.CategoryID].CategoryName == category {
+				menus = append(menus, menu)
+			}
+		}
+	})
+	return menus, nil
+}
+
+func (r memoryMenus) FindByID(ctx context.Context, id int) (menu models.Menu, err error) {
+	r.s.locked(func(d *memoryData) {
+		var ok bool
+		if menu, ok = d.menus[id]; !ok {
+			err = ErrNotFound
+		}
+	})
+	return menu, err
+}
+
+func (r memoryMenus) FindByIDs(ctx context.Context, ids []int) (menus []models.Menu, err error) {
+	r.s.locked(func(d *memoryData) {
+		seen := map[int]bool{}
+		for _, id := range ids {
+			if menu, ok := d.menus[id]; ok && !seen[id] {
+				menus = append(menus, menu)
+				seen[id] = true
+			}
+		}
+	})
+	return menus, nil
+}
+
+func (r memoryMenus) Create(ctx context.Context, menu *models.Menu) error {
+	r.s.locked(func(d *memoryData) {
+		menu.ID = d.nextID(menu.ID)
+		d.menus[menu.ID] = *menu
+	})
+	return nil
+}
+
+func (r memoryMenus) Update(ctx context.Context, menu models.Menu) error {
+	r.s.locked(func(d *memoryData) {
+		existing, ok := d.menus[menu.ID]
+		if !ok {
+			return
+		}
+		existing.Name = menu.Name
+		existing.Price = menu.Price
+		existing.Description = menu.Description
+		existing.CategoryID = menu.CategoryID
+		existing.ImageURL = menu.ImageURL
+		existing.IsAvailable = menu.IsAvailable
+		d.menus[menu.ID] = existing
+	})
+	return nil
+}
+
+type memoryOrders struct{ s *MemoryStore }
+
+// load returns the order with its payment and details, as the gorm repository preloads them.
+func (d *memoryData) load(order models.Order) models.Order {
+	order.Payment = models.Payment{}
+	for _, payment := range sortedValues(d.payments, func(p models.Payment) int { return p.ID }) {
+		if payment.OrderID == order.ID {
+			order.Payment = payment
+			break
+		}
+	}
+	order.OrderDetails = nil
+	for _, detail := range sortedValues(d.orderDetails, func(od models.OrderDetail) int { return od.ID }) {
+		if detail.OrderID == order.ID {
+			detail.Menu = d.menus[detail.MenuID]
+			order.OrderDetails = append(order.OrderDetails, detail)
+		}
+	}
+	return order
+}
+
+func (r memoryOrders) List(ctx context.Context, filter OrderFilter) (orders []models.Order, err error) {
+	r.s.locked(func(d *memoryData) {
+		orders = []models.Order{}
+		for _, order := range sortedValues(d.orders, func(o models.Order) int { return o.ID }) {
+			if order = d.load(order); filter.matches(order) {
+				orders = append(orders, order)
+			}
+		}
+	})
+	return orders, nil
+}
+
+func (r memoryOrders) FindByID(ctx context.Context, id int) (order models.Order, err error) {
+	r.s.locked(func(d *memoryData) {
+		stored, ok := d.orders[id]
+		if !ok {
+			err = ErrNotFound
+			return
+		}
+		order = d.load(stored)
+	})
+	return order, err
+}
+
+func (r memoryOrders) FindByTransactionCode(ctx context.Context, transactionCode string) (order models.Order, err error) {
+	err = ErrNotFound
+	r.s.locked(func(d *memoryData) {
+		for _, payment := range d.payments {
+			if stored, ok := d.orders[payment.OrderID]; ok && payment.TransactionCode == transactionCode {
+				order, err = d.load(stored), nil
+				return
+			}
+		}
+	})
+	return order, err
+}
+
+func (r memoryOrders) Create(ctx context.Context, order *models.Order) error {
+	r.s.locked(func(d *memoryData) {
+		order.ID = d.nextID(order.ID)
+		stored := *order
+		stored.Payment, stored.OrderDetails = models.Payment{}, nil
+		d.orders[order.ID] = stored
+
+		for i := range order.OrderDetails {
+			detail := &order.OrderDetails[i]
+			detail.ID = d.nextID(detail.ID)
+			detail.OrderID = order.ID
+			storedDetail := *detail
+			storedDetail.Menu = models.Menu{}
+			d.orderDetails[detail.ID] = storedDetail
+		}
+	})
+	return nil
+}
+
+func (r memoryOrders) UpdateStatus(ctx context.Context, id int, status string) error {
+	r.s.locked(func(d *memoryData) {
+		if order, ok := d.orders[id]; ok {
+			order.OrderStatus = status
+			d.orders[id] = order
+		}
+	})
+	return nil
+}
+
+type memoryPayments struct{ s *MemoryStore }
+
+func (r memoryPayments) Create(ctx context.Context, payment *models.Payment) (err error) {
+	r.s.locked(func(d *memoryData) {
+		// Mirrors the unique index on payments.transaction_code
+		for _, existing := range d.payments {
+			if payment.TransactionCode != "" && existing.TransactionCode == payment.TransactionCode {
+				err = fmt.Errorf("duplicate transaction code %s", payment.TransactionCode)
+				return
+			}
+		}
+		payment.ID = d.nextID(payment.ID)
+		d.payments[payment.ID] = *payment
+	})
+	return err
+}
+
+func (r memoryPayments) UpdateStatusByOrderID(ctx context.Context, orderID int, status string) error {
+	r.s.locked(func(d *memoryData) {
+		for id, payment := range d.payments {
+			if payment.OrderID == orderID {
+				payment.PaymentStatus = status
+				d.payments[id] = payment
+			}
+		}
+	})
+	return nil
+}
+
+func (r memoryPayments) RecordStatusCheck(ctx context.Context, orderID int, status string, checkedAt time.Time) error {
+	r.s.locked(func(d *memoryData) {
+		for id, payment := range d.payments {
+			if payment.OrderID == orderID {
+				payment.PaymentStatus = status
+				payment.StatusCheckedAt = &checkedAt
+				d.payments[id] = payment
+			}
+		}
+	})
+	return nil
+}
+
+type memoryPaymentMethods struct{ s *MemoryStore }
+
+func (r memoryPaymentMethods) List(ctx context.Context) (methods []models.PaymentMethod, err error) {
+	r.s.locked(func(d *memoryData) {
+		methods = sortedValues(d.paymentMethods, func(m models.PaymentMethod) int { return m.ID })
+	})
+	return methods, nil
+}
+
+func (r memoryPaymentMethods) FindByID(ctx context.Context, id int) (method models.PaymentMethod, err error) {
+	r.s.locked(func(d *memoryData) {
+		var ok bool
+		if method, ok = d.paymentMethods[id]; !ok {
+			err = ErrNotFound
+		}
+	})
+	return method, err
+}
+
+type memorySchedules struct{ s *MemoryStore }
+
+func (r memorySchedules) ListSchedules(ctx context.Context) (schedules []models.AvailabilitySchedule, err error) {
+	r.s.locked(func(d *memoryData) {
+		schedules = sortedValues(d.schedules, func(s models.AvailabilitySchedule) int { return s.ID })
+	})
+	return schedules, nil
+}
+
+func (r memorySchedules) ListPriceRules(ctx context.Context) (rules []models.PriceRule, err error) {
+	r.s.locked(func(d *memoryData) {
+		rules = sortedValues(d.priceRules, func(p models.PriceRule) int { return p.ID })
+	})
+	return rules, nil
+}
+
+type memoryEmails struct{ s *MemoryStore }
+
+func (r memoryEmails) ActiveTemplate(ctx context.Context, name, lang string) (template *models.EmailTemplate, err error) {
+	r.s.locked(func(d *memoryData) {
+		for _, stored := range d.emailTemplates {
+			if stored.Name == name && strings.EqualFold(stored.Language, lang) && (template == nil || stored.Version > template.Version) {
+				stored := stored
+				template = &stored
+			}
+		}
+	})
+	return template, nil
+}
+
+func (r memoryEmails) CreateOutbox(ctx context.Context, email *models.EmailOutbox) error {
+	r.s.locked(func(d *memoryData) {
+		email.ID = d.nextID(email.ID)
+		d.outbox[email.ID] = *email
+	})
+	return nil
+}
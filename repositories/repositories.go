@@ -0,0 +1,126 @@
+// Package repositories hides how data is stored from the services. Store has a
+// gorm implementation for Postgres and an in-memory one for tests.
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/models"
+)
+
+// ErrNotFound is returned when the requested record does not exist.
+var ErrNotFound = errors.New("record not found")
+
+// Store gives access to every repository. Repositories obtained from the Store passed to
+// Transaction's fn write inside that transaction.
+type Store interface {
+	Users() UserRepository
+	Categories() CategoryRepository
+	Menus() MenuRepository
+	Orders() OrderRepository
+	Payments() PaymentRepository
+	PaymentMethods() PaymentMethodRepository
+	Schedules() ScheduleRepository
+	Emails() EmailRepository
+
+	// Transaction commits every write made by fn, or none when fn returns an error.
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+}
+
+type UserRepository interface {
+	FindByUsername(ctx context.Context, username string) (models.User, error)
+}
+
+type CategoryRepository interface {
+	List(ctx context.Context) ([]models.Category, error)
+	FindByID(ctx context.Context, id int) (models.Category, error)
+	Create(ctx context.Context, category *models.Category) error
+	// Update writes the non-empty fields of category to the category with id.
+	Update(ctx context.Context, id int, category models.Category) error
+}
+
+type MenuRepository interface {
+	// List returns every menu, or those in the category with that name when category is not empty.
+	List(ctx context.Context, category string) ([]models.Menu, error)
+	FindByID(ctx context.Context, id int) (models.Menu, error)
+	// FindByIDs returns the menus that exist among ids, in no particular order.
+	FindByIDs(ctx context.Context, ids []int) ([]models.Menu, error)
+	Create(ctx context.Context, menu *models.Menu) error
+	// Update overwrites the editable fields of the menu with menu.ID, zero values included.
+	Update(ctx context.Context, menu models.Menu) error
+}
+
+// Orders are returned with their Payment and OrderDetails, each detail with its Menu.
+type OrderRepository interface {
+	List(ctx context.Context, filter OrderFilter) ([]models.Order, error)
+	FindByID(ctx context.Context, id int) (models.Order, error)
+	FindByTransactionCode(ctx context.Context, transactionCode string) (models.Order, error)
+	// Create inserts the order and its OrderDetails, setting their IDs. Payment is not saved.
+	Create(ctx context.Context, order *models.Order) error
+	UpdateStatus(ctx context.Context, id int, status string) error
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *models.Payment) error
+	UpdateStatusByOrderID(ctx context.Context, orderID int, status string) error
+	// RecordStatusCheck stores the status Midtrans reported for an order at checkedAt.
+	RecordStatusCheck(ctx context.Context, orderID int, status string, checkedAt time.Time) error
+}
+
+type PaymentMethodRepository interface {
+	List(ctx context.Context) ([]models.PaymentMethod, error)
+	FindByID(ctx context.Context, id int) (models.PaymentMethod, error)
+}
+
+type ScheduleRepository interface {
+	ListSchedules(ctx context.Context) ([]models.AvailabilitySchedule, error)
+	ListPriceRules(ctx context.Context) ([]models.PriceRule, error)
+}
+
+type EmailRepository interface {
+	// ActiveTemplate returns the latest stored version of a template, or nil when staff
+	// never edited it in that language.
+	ActiveTemplate(ctx context.Context, name, lang string) (*models.EmailTemplate, error)
+	CreateOutbox(ctx context.Context, email *models.EmailOutbox) error
+}
+
+// OrderFilter narrows order listings. Empty fields match every order. From and To are
+// inclusive YYYY-MM-DD dates in UTC, Email and PaymentMethod match case-insensitively.
+type OrderFilter struct {
+	Status        string
+	Email         string
+	PaymentMethod string
+	From          string
+	To            string
+}
+
+// before returns the exclusive upper bound for order_date, the day after To.
+func (f OrderFilter) before() string {
+	day, err := time.Parse("2006-01-02", f.To)
+	if err != nil {
+		return f.To
+	}
+	return day.AddDate(0, 0, 1).Format("2006-01-02")
+}
+
+func (f OrderFilter) matches(order models.Order) bool {
+	if f.Status != "" && order.OrderStatus != f.Status {
+		return false
+	}
+	if f.Email != "" && !strings.EqualFold(order.Email, f.Email) {
+		return false
+	}
+	if f.PaymentMethod != "" && !strings.EqualFold(order.Payment.PaymentMethod, f.PaymentMethod) {
+		return false
+	}
+	if f.From != "" && order.OrderDate < f.From {
+		return false
+	}
+	if f.To != "" && order.OrderDate >= f.before() {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// requireStaff rejects requests without a valid staff JWT signed with secret and stores
+// its claims under "claims".
+func requireStaff(secret string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Ambil header Authorization
+		authorization := ctx.GetHeader("Authorization")
+		if authorization == "" {
+			apperror.Abort(ctx, apperror.Unauthorized("Authorization header is required"))
+			return
+		}
+
+		// Periksa format Bearer
+		if !strings.HasPrefix(authorization, "Bearer ") {
+			apperror.Abort(ctx, apperror.Unauthorized("Invalid token format"))
+			return
+		}
+
+		// Ekstrak token
+		tokenString := strings.TrimPrefix(authorization, "Bearer ")
+
+		// Validasi token
+		claims, err := utils.ValidateJWT(secret, tokenString)
+		if err != nil {
+			// Only an expired token is worth explaining, parse errors are not client facing
+			var message *i18n.Error
+			if !errors.As(err, &message) {
+				message = &i18n.Error{Format: "invalid token"}
+			}
+			apperror.Abort(ctx, &apperror.Error{Code: apperror.CodeUnauthorized, Status: http.StatusUnauthorized, Message: message, Err: err})
+			return
+		}
+
+		// Simpan klaim ke context untuk digunakan pada handler berikutnya
+		ctx.Set("claims", claims)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package router
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/ratelimit"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/dimassfeb-09/pestapasta-be/services"
+	"github.com/dimassfeb-09/pestapasta-be/tracing"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// Container holds the services and settings handlers depend on. Every API version
+// registers its own handlers against the same container, so a version can change its
+// payloads while sharing the services behind them.
+type Container struct {
+	Config     utils.Config
+	DB         *gorm.DB
+	Users      *services.UserService
+	Categories *services.CategoryService
+	Menus      *services.MenuService
+	Payments   *services.PaymentService
+	Orders     *services.OrderService
+	Limiter    *ratelimit.Limiter // Nil when rate limiting is disabled
+
+	Draining       *atomic.Bool           // Set once shutdown starts, fails readiness
+	CapturedEmails *helpers.CaptureMailer // Captured emails can be browsed when set
+}
+
+// NewContainer wires the services on top of db, paying through gateway.
+func NewContainer(cfg utils.Config, db *gorm.DB, gateway services.PaymentGateway) *Container {
+	store := repositories.NewGormStore(db)
+	app := &Container{
+		Config:     cfg,
+		DB:         db,
+		Users:      services.NewUserService(store, cfg.SecretKeyJWT),
+		Categories: services.NewCategoryService(store),
+		Menus:      services.NewMenuService(store),
+		Payments:   services.NewPaymentService(store, gateway, cfg.Company, time.Duration(cfg.Midtrans.StatusRefreshSeconds)*time.Second),
+		Orders:     services.NewOrderService(store, gateway, cfg.Company),
+		Draining:   new(atomic.Bool),
+	}
+	if cfg.RateLimit.Enabled {
+		app.Limiter = ratelimit.New(ratelimit.NewMemoryStore(), cfg.RateLimit)
+	}
+	return app
+}
+
+// tracedDB hands handlers a connection whose queries join the request's trace.
+func (app *Container) tracedDB(c *gin.Context) *gorm.DB {
+	return tracing.WithContext(app.DB, c.Request.Context())
+}
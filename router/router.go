@@ -0,0 +1,68 @@
+// Package router mounts the HTTP API: probes and metrics at the root, the API under
+// /api/v1, and the unversioned paths clients used before as deprecated aliases of v1.
+package router
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/controllers"
+	"github.com/dimassfeb-09/pestapasta-be/metrics"
+	"github.com/dimassfeb-09/pestapasta-be/openapi"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// V1 is where version 1 of the API is mounted.
+const V1 = "/api/v1"
+
+// LegacyDeprecatedAt is when the unversioned paths were deprecated in favour of V1.
+var LegacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// Register mounts every route on r.
+func Register(r *gin.Engine, app *Container) {
+	// Probes and metrics are for the platform, not API clients, and are not versioned
+	r.GET("/healthz", controllers.Healthz)
+	r.GET("/readyz", func(c *gin.Context) {
+		controllers.Readyz(c, app.DB, app.Draining)
+	})
+	r.GET("/version", controllers.GetVersion)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	var api []gin.HandlerFunc
+	if app.Limiter != nil {
+		// Trimming V1 lets a route and its legacy alias share one bucket
+		api = append(api, app.Limiter.Middleware(V1))
+	}
+	registerV1(r.Group(V1, api...), app)
+	registerV1(r.Group("/", append([]gin.HandlerFunc{deprecated(V1)}, api...)...), app)
+}
+
+// deprecated marks responses of legacy aliases with a Deprecation header (RFC 9745) and
+// links to the same path under successor.
+func deprecated(successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", LegacyDeprecatedAt.Unix())
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor, c.Request.URL.Path))
+		c.Next()
+	}
+}
+
+var (
+	specOnce sync.Once
+	spec     openapi.Document
+)
+
+// Spec describes every route Register mounts.
+func Spec() openapi.Document {
+	specOnce.Do(func() {
+		spec = openapi.Build(utils.GetBuildInfo().Version,
+			openapi.Group{Routes: openapi.SystemRoutes},
+			openapi.Group{Prefix: V1, Routes: openapi.Routes},
+			openapi.Group{Routes: openapi.Routes, Deprecated: true},
+		)
+	})
+	return spec
+}
@@ -0,0 +1,71 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// TestRoutesAreDocumented keeps openapi.Routes in sync with the routes the server registers.
+func TestRoutesAreDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	Register(r, &Container{Draining: new(atomic.Bool), CapturedEmails: &helpers.CaptureMailer{}})
+
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		registered[route.Method+" "+openapi.Path(route.Path)] = true
+	}
+	documented := map[string]bool{}
+	for path, item := range Spec().Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	var missing, stale []string
+	for route := range registered {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			stale = append(stale, route)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	if len(missing) > 0 {
+		t.Errorf("routes missing from openapi.Routes:\n%s", strings.Join(missing, "\n"))
+	}
+	if len(stale) > 0 {
+		t.Errorf("openapi.Routes documents routes that are not registered:\n%s", strings.Join(stale, "\n"))
+	}
+}
+
+func TestLegacyPathsAreDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	Register(r, &Container{Draining: new(atomic.Bool)})
+
+	for path, want := range map[string]string{"/openapi.json": "@1792368000", V1 + "/openapi.json": "", "/healthz": ""} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if got := w.Header().Get("Deprecation"); got != want {
+			t.Errorf("%s: got Deprecation %q, want %q", path, got, want)
+		}
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if got := w.Header().Get("Link"); got != `</api/v1/openapi.json>; rel="successor-version"` {
+		t.Errorf("got Link %q", got)
+	}
+}
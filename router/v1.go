@@ -0,0 +1,217 @@
+package router
+
+import (
+	"github.com/dimassfeb-09/pestapasta-be/controllers"
+	"github.com/dimassfeb-09/pestapasta-be/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// registerV1 mounts the handlers of version 1 of the API on g. Describe new routes in
+// openapi.Routes as well, TestRoutesAreDocumented fails otherwise.
+func registerV1(g *gin.RouterGroup, app *Container) {
+	// Group untuk endpoint publik (tidak memerlukan autentikasi)
+	public := g.Group("/")
+	{
+		public.GET("/openapi.json", openapi.Handler(Spec()))
+
+		public.GET("/docs", openapi.DocsHandler())
+
+		public.POST("/login", func(c *gin.Context) {
+			controllers.HandleLogin(c, app.Users)
+		})
+
+		public.POST("/checkout", func(c *gin.Context) {
+			controllers.HandleCheckout(c, app.Orders)
+		})
+
+		public.GET("/menus", func(c *gin.Context) {
+			controllers.GetMenu(c, app.Menus)
+		})
+
+		public.GET("/menus/:id", func(c *gin.Context) {
+			controllers.GetMenuByID(c, app.Menus)
+		})
+
+		public.GET("/menus/:id/reviews", func(c *gin.Context) {
+			controllers.GetMenuReviews(c, app.tracedDB(c))
+		})
+
+		public.POST("/reviews", func(c *gin.Context) {
+			controllers.CreateReview(c, app.tracedDB(c))
+		})
+
+		public.GET("/categories", func(c *gin.Context) {
+			controllers.GetCategories(c, app.Categories)
+		})
+
+		public.GET("/categories/:id", func(c *gin.Context) {
+			controllers.GetCategoriesByID(c, app.Categories)
+		})
+
+		public.GET("/payment_methods", func(c *gin.Context) {
+			controllers.GetPaymentMethods(c, app.Payments)
+		})
+
+		public.GET("/orders/:id/status", func(c *gin.Context) {
+			controllers.CheckOrderStatusByID(c, app.Payments)
+		})
+
+		// :id is the transaction code here, gin allows only one wildcard name per segment
+		public.GET("/orders/:id/receipt.pdf", func(c *gin.Context) {
+			controllers.GetOrderReceipt(c, app.Orders, app.Config.Company)
+		})
+
+		public.GET("/orders/:id", func(c *gin.Context) {
+			controllers.GetOrderByID(c, app.Orders, app.Payments)
+		})
+
+	}
+
+	// Group untuk endpoint yang memerlukan autentikasi
+	auth := g.Group("/")
+	auth.Use(requireStaff(app.Config.SecretKeyJWT))
+	{
+		auth.GET("/orders", func(c *gin.Context) {
+			controllers.GetAllOrders(c, app.Orders)
+		})
+
+		auth.PUT("/orders/:id/status", func(c *gin.Context) {
+			controllers.UpdateOrderStatus(c, app.Orders)
+		})
+
+		auth.POST("/menus", func(c *gin.Context) {
+			controllers.CreateNewProduct(c, app.Menus)
+		})
+
+		auth.PUT("/menus/:id", func(c *gin.Context) {
+			controllers.UpdateProduct(c, app.Menus)
+		})
+
+		auth.PATCH("/menus/:id", func(c *gin.Context) {
+			controllers.PatchProduct(c, app.Menus)
+		})
+
+		auth.POST("/categories", func(c *gin.Context) {
+			controllers.CreateCategory(c, app.Categories)
+		})
+
+		auth.PUT("/categories/:id", func(c *gin.Context) {
+			controllers.UpdateCategory(c, app.Categories)
+		})
+
+		auth.PATCH("/categories/:id", func(c *gin.Context) {
+			controllers.PatchCategory(c, app.Categories)
+		})
+
+		auth.GET("/reports/sales", func(c *gin.Context) {
+			controllers.GetSalesReport(c, app.tracedDB(c))
+		})
+
+		auth.GET("/reports/menus", func(c *gin.Context) {
+			controllers.GetMenuSalesReport(c, app.tracedDB(c))
+		})
+
+		auth.GET("/reports/categories", func(c *gin.Context) {
+			controllers.GetCategorySalesReport(c, app.tracedDB(c))
+		})
+
+		auth.GET("/reports/payment_methods", func(c *gin.Context) {
+			controllers.GetPaymentMethodSalesReport(c, app.tracedDB(c))
+		})
+
+		auth.GET("/emails", func(c *gin.Context) {
+			controllers.GetEmails(c, app.tracedDB(c))
+		})
+
+		auth.POST("/emails/:id/resend", func(c *gin.Context) {
+			controllers.ResendEmail(c, app.tracedDB(c))
+		})
+
+		auth.GET("/email_templates", func(c *gin.Context) {
+			controllers.GetEmailTemplates(c, app.tracedDB(c))
+		})
+
+		auth.GET("/email_templates/:name", func(c *gin.Context) {
+			controllers.GetEmailTemplateVersions(c, app.tracedDB(c))
+		})
+
+		auth.PUT("/email_templates/:name", func(c *gin.Context) {
+			controllers.UpdateEmailTemplate(c, app.tracedDB(c))
+		})
+
+		auth.POST("/email_templates/:name/preview", func(c *gin.Context) {
+			controllers.PreviewEmailTemplate(c, app.tracedDB(c), app.Config.Company)
+		})
+
+		auth.GET("/exports/orders.csv", func(c *gin.Context) {
+			controllers.ExportOrders(c, app.tracedDB(c), controllers.ExportCSV)
+		})
+
+		auth.GET("/exports/orders.xlsx", func(c *gin.Context) {
+			controllers.ExportOrders(c, app.tracedDB(c), controllers.ExportXLSX)
+		})
+
+		auth.GET("/exports/reports.csv", func(c *gin.Context) {
+			controllers.ExportSalesReport(c, app.tracedDB(c), controllers.ExportCSV)
+		})
+
+		auth.GET("/exports/reports.xlsx", func(c *gin.Context) {
+			controllers.ExportSalesReport(c, app.tracedDB(c), controllers.ExportXLSX)
+		})
+
+		auth.GET("/reviews", func(c *gin.Context) {
+			controllers.GetReviews(c, app.tracedDB(c))
+		})
+
+		auth.PUT("/reviews/:id/status", func(c *gin.Context) {
+			controllers.ModerateReview(c, app.tracedDB(c))
+		})
+
+		auth.DELETE("/reviews/:id", func(c *gin.Context) {
+			controllers.DeleteReview(c, app.tracedDB(c))
+		})
+
+		auth.GET("/schedules", func(c *gin.Context) {
+			controllers.GetSchedules(c, app.tracedDB(c))
+		})
+
+		auth.POST("/schedules", func(c *gin.Context) {
+			controllers.CreateSchedule(c, app.tracedDB(c))
+		})
+
+		auth.PUT("/schedules/:id", func(c *gin.Context) {
+			controllers.UpdateSchedule(c, app.tracedDB(c))
+		})
+
+		auth.DELETE("/schedules/:id", func(c *gin.Context) {
+			controllers.DeleteSchedule(c, app.tracedDB(c))
+		})
+
+		auth.GET("/price_rules", func(c *gin.Context) {
+			controllers.GetPriceRules(c, app.tracedDB(c))
+		})
+
+		auth.POST("/price_rules", func(c *gin.Context) {
+			controllers.CreatePriceRule(c, app.tracedDB(c))
+		})
+
+		auth.PUT("/price_rules/:id", func(c *gin.Context) {
+			controllers.UpdatePriceRule(c, app.tracedDB(c))
+		})
+
+		auth.DELETE("/price_rules/:id", func(c *gin.Context) {
+			controllers.DeletePriceRule(c, app.tracedDB(c))
+		})
+	}
+
+	// Let developers read captured emails without sending real ones
+	if app.CapturedEmails != nil {
+		g.GET("/dev/emails", func(c *gin.Context) {
+			controllers.GetCapturedEmails(c, app.CapturedEmails)
+		})
+
+		g.GET("/dev/emails/:id", func(c *gin.Context) {
+			controllers.GetCapturedEmail(c, app.CapturedEmails)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/migrations"
+	"github.com/dimassfeb-09/pestapasta-be/seed"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/jinzhu/gorm"
+)
+
+// runSeed handles `run-app seed [-file fixtures.yaml] [-reset]`. Without -file the
+// built-in sample dataset is used, -reset empties every table first.
+func runSeed(db *gorm.DB, cfg utils.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	file := flags.String("file", "", "YAML or JSON fixtures, defaults to the built-in sample data")
+	reset := flags.Bool("reset", false, "empty every table before seeding")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Sample data and its well-known admin password must never reach production
+	if cfg.IsProduction() {
+		return errors.New("seed is disabled in production")
+	}
+
+	fixtures, err := seed.Load(*file)
+	if err != nil {
+		return err
+	}
+	if err := migrations.Check(context.Background(), db.DB()); err != nil {
+		return err
+	}
+
+	if *reset {
+		if err := seed.Reset(db); err != nil {
+			return fmt.Errorf("failed to reset database: %w", err)
+		}
+		fmt.Println("Emptied every table")
+	}
+
+	result, err := seed.Run(db, fixtures, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Seeded database: %d created, %d updated, %d skipped\n", result.Created, result.Updated, result.Skipped)
+	return nil
+}
@@ -0,0 +1,413 @@
+// Package seed fills a database with sample data for local development and tests.
+// Fixtures are YAML files, JSON works too because every JSON document is valid YAML.
+package seed
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/jinzhu/gorm"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed fixtures/default.yaml
+var defaultFixtures []byte
+
+// Fixtures is a sample dataset. Menus refer to categories, and orders to menus and
+// payment methods, by name or code so fixture files never depend on database IDs.
+type Fixtures struct {
+	Users          []User          `yaml:"users"`
+	Categories     []Category      `yaml:"categories"`
+	Menus          []Menu          `yaml:"menus"`
+	PaymentMethods []PaymentMethod `yaml:"payment_methods"`
+	Orders         []Order         `yaml:"orders"`
+}
+
+// User is a staff account, Password is in plain text and hashed when seeded.
+type User struct {
+	Name     string `yaml:"name"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+type Category struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+// Menu is available unless IsAvailable is set to false.
+type Menu struct {
+	Name        string  `yaml:"name"`
+	Category    string  `yaml:"category"`
+	Price       float64 `yaml:"price"`
+	Description string  `yaml:"description"`
+	ImageURL    string  `yaml:"image_url"`
+	IsAvailable *bool   `yaml:"is_available"`
+}
+
+type PaymentMethod struct {
+	Name          string `yaml:"name"`
+	Code          string `yaml:"code"`
+	AccountNumber string `yaml:"account_number"`
+	AccountName   string `yaml:"account_name"`
+}
+
+// Order is a historic order, identified by its transaction code. OrderDate is
+// "2006-01-02 15:04:05" in UTC, when empty the order is placed DaysAgo days before seeding
+// so reports always have recent data.
+type Order struct {
+	TransactionCode string      `yaml:"transaction_code"`
+	Name            string      `yaml:"name"`
+	Email           string      `yaml:"email"`
+	Language        string      `yaml:"language"`
+	OrderDate       string      `yaml:"order_date"`
+	DaysAgo         int         `yaml:"days_ago"`
+	PaymentMethod   string      `yaml:"payment_method"` // Payment method code
+	Status          string      `yaml:"status"`
+	PaymentStatus   string      `yaml:"payment_status"`
+	Items           []OrderItem `yaml:"items"`
+}
+
+type OrderItem struct {
+	Menu     string `yaml:"menu"`
+	Quantity int    `yaml:"quantity"`
+	Notes    string `yaml:"notes"`
+}
+
+// Result counts the fixtures Run created, updated, or skipped because they were already seeded.
+type Result struct {
+	Created int
+	Updated int
+	Skipped int
+}
+
+// tables lists the application tables emptied by Reset, schema_migrations is kept.
+var tables = []string{
+	"email_templates", "email_outbox", "reviews", "price_rules", "availability_schedules",
+	"payments", "order_details", "orders", "payment_methods", "menus", "categories", "users",
+}
+
+// Load reads fixtures from path, or the built-in sample dataset when path is empty.
+func Load(path string) (Fixtures, error) {
+	data := defaultFixtures
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return Fixtures{}, fmt.Errorf("failed to read fixtures: %w", err)
+		}
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates fixtures. Unknown fields are rejected to catch typos.
+func Parse(data []byte) (Fixtures, error) {
+	var fixtures Fixtures
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixtures); err != nil {
+		return Fixtures{}, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	return fixtures, fixtures.Validate()
+}
+
+// Validate reports every missing field and unresolved reference at once.
+func (f Fixtures) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	for i, user := range f.Users {
+		if user.Username == "" || user.Password == "" {
+			add("users[%d] needs a username and password", i)
+		}
+	}
+	categories := map[string]bool{}
+	for i, category := range f.Categories {
+		if category.Name == "" {
+			add("categories[%d] needs a name", i)
+		}
+		categories[category.Name] = true
+	}
+	menus := map[string]bool{}
+	for i, menu := range f.Menus {
+		if menu.Name == "" {
+			add("menus[%d] needs a name", i)
+		}
+		if !categories[menu.Category] {
+			add("menu %q refers to unknown category %q", menu.Name, menu.Category)
+		}
+		if menu.Price <= 0 {
+			add("menu %q needs a price", menu.Name)
+		}
+		menus[menu.Name] = true
+	}
+	paymentMethods := map[string]bool{}
+	for i, method := range f.PaymentMethods {
+		if method.Name == "" || method.Code == "" {
+			add("payment_methods[%d] needs a name and code", i)
+		}
+		paymentMethods[method.Code] = true
+	}
+	for i, order := range f.Orders {
+		if order.TransactionCode == "" {
+			add("orders[%d] needs a transaction_code", i)
+		}
+		if order.OrderDate != "" {
+			if _, err := time.Parse("2006-01-02 15:04:05", order.OrderDate); err != nil {
+				add("order %s has order_date %q, expected YYYY-MM-DD HH:MM:SS", order.TransactionCode, order.OrderDate)
+			}
+		}
+		if !paymentMethods[order.PaymentMethod] {
+			add("order %s refers to unknown payment method %q", order.TransactionCode, order.PaymentMethod)
+		}
+		if len(order.Items) == 0 {
+			add("order %s has no items", order.TransactionCode)
+		}
+		for _, item := range order.Items {
+			if !menus[item.Menu] {
+				add("order %s refers to unknown menu %q", order.TransactionCode, item.Menu)
+			}
+			if item.Quantity <= 0 {
+				add("order %s needs a positive quantity for %q", order.TransactionCode, item.Menu)
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid fixtures: %s", strings.Join(problems, "; "))
+}
+
+// Run writes the fixtures in one transaction. It is idempotent: users, categories, menus
+// and payment methods are matched by username, name or code and updated in place, and
+// orders that already exist are left alone.
+func Run(db *gorm.DB, fixtures Fixtures, now time.Time) (Result, error) {
+	var result Result
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return result, tx.Error
+	}
+	defer tx.Rollback()
+
+	for _, user := range fixtures.Users {
+		if err := seedUser(tx, user, &result); err != nil {
+			return result, fmt.Errorf("failed to seed user %s: %w", user.Username, err)
+		}
+	}
+
+	categoryIDs := map[string]int{}
+	for _, category := range fixtures.Categories {
+		row := models.Category{CategoryName: category.Name, Description: category.Description}
+		columns := map[string]interface{}{"description": row.Description}
+		id, err := upsert(tx, &row, columns, "category_name = ?", category.Name, &result)
+		if err != nil {
+			return result, fmt.Errorf("failed to seed category %s: %w", category.Name, err)
+		}
+		categoryIDs[category.Name] = id
+	}
+
+	menus := map[string]models.Menu{}
+	for _, menu := range fixtures.Menus {
+		row := models.Menu{
+			Name:        menu.Name,
+			Price:       menu.Price,
+			Description: menu.Description,
+			CategoryID:  categoryIDs[menu.Category],
+			ImageURL:    menu.ImageURL,
+			IsAvailable: menu.IsAvailable == nil || *menu.IsAvailable,
+		}
+		columns := map[string]interface{}{
+			"price":        row.Price,
+			"description":  row.Description,
+			"category_id":  row.CategoryID,
+			"image_url":    row.ImageURL,
+			"is_available": row.IsAvailable,
+		}
+		id, err := upsert(tx, &row, columns, "name = ?", menu.Name, &result)
+		if err != nil {
+			return result, fmt.Errorf("failed to seed menu %s: %w", menu.Name, err)
+		}
+		row.ID = id
+		menus[menu.Name] = row
+	}
+
+	paymentMethods := map[string]models.PaymentMethod{}
+	for _, method := range fixtures.PaymentMethods {
+		row := models.PaymentMethod{
+			Name:          method.Name,
+			Code:          method.Code,
+			AccountNumber: method.AccountNumber,
+			AccountName:   method.AccountName,
+		}
+		columns := map[string]interface{}{
+			"name":           row.Name,
+			"account_number": row.AccountNumber,
+			"account_name":   row.AccountName,
+		}
+		id, err := upsert(tx, &row, columns, "code = ?", method.Code, &result)
+		if err != nil {
+			return result, fmt.Errorf("failed to seed payment method %s: %w", method.Code, err)
+		}
+		row.ID = id
+		paymentMethods[method.Code] = row
+	}
+
+	for _, order := range fixtures.Orders {
+		if err := seedOrder(tx, order, menus, paymentMethods[order.PaymentMethod], now, &result); err != nil {
+			return result, fmt.Errorf("failed to seed order %s: %w", order.TransactionCode, err)
+		}
+	}
+
+	return result, tx.Commit().Error
+}
+
+// Reset empties every application table and restarts their ID sequences.
+func Reset(db *gorm.DB) error {
+	return db.Exec("TRUNCATE TABLE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error
+}
+
+func seedUser(tx *gorm.DB, fixture User, result *Result) error {
+	var user models.User
+	err := tx.Where("username = ?", fixture.Username).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	exists := err == nil
+
+	// Keep the existing hash while it still matches, bcrypt salts differ on every run
+	passwordMatches := exists && bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(fixture.Password)) == nil
+	if exists && passwordMatches && user.Name == fixture.Name {
+		result.Skipped++
+		return nil
+	}
+
+	user.Name = fixture.Name
+	user.Username = fixture.Username
+	if !passwordMatches {
+		hash, err := bcrypt.GenerateFromPassword([]byte(fixture.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		user.Password = string(hash)
+	}
+
+	if exists {
+		result.Updated++
+		return tx.Save(&user).Error
+	}
+	result.Created++
+	return tx.Create(&user).Error
+}
+
+// upsert creates row, or updates columns of the existing row matching where, and returns
+// its ID. Columns not listed, such as menu ratings, keep the values the app computed.
+func upsert(tx *gorm.DB, row interface{}, columns map[string]interface{}, where string, key string, result *Result) (int, error) {
+	scope := tx.NewScope(row)
+	var existing struct{ ID int }
+	err := tx.Table(scope.TableName()).Select("id").Where(where, key).Scan(&existing).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := tx.Create(row).Error; err != nil {
+			return 0, err
+		}
+		result.Created++
+		id, _ := scope.PrimaryKeyValue().(int)
+		return id, nil
+	case err != nil:
+		return 0, err
+	}
+
+	// A map also writes zero values, so is_available: false is applied
+	if err := tx.Table(scope.TableName()).Where("id = ?", existing.ID).Updates(columns).Error; err != nil {
+		return 0, err
+	}
+	result.Updated++
+	return existing.ID, nil
+}
+
+func seedOrder(tx *gorm.DB, fixture Order, menus map[string]models.Menu, method models.PaymentMethod, now time.Time, result *Result) error {
+	var count int
+	if err := tx.Model(&models.Payment{}).Where("transaction_code = ?", fixture.TransactionCode).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		result.Skipped++
+		return nil
+	}
+
+	orderDate := fixture.OrderDate
+	if orderDate == "" {
+		orderDate = now.AddDate(0, 0, -fixture.DaysAgo).UTC().Format("2006-01-02 15:04:05")
+	}
+	status := fixture.Status
+	if status == "" {
+		status = "Pending"
+	}
+	paymentStatus := fixture.PaymentStatus
+	if paymentStatus == "" {
+		paymentStatus = "pending"
+	}
+
+	total := 0.0
+	for _, item := range fixture.Items {
+		total += menus[item.Menu].Price * float64(item.Quantity)
+	}
+
+	order := models.Order{
+		OrderDate:   orderDate,
+		Email:       fixture.Email,
+		Name:        fixture.Name,
+		TotalPrice:  total,
+		Tax:         total * models.TaxRate,
+		OrderStatus: status,
+		Language:    i18n.Resolve(fixture.Language),
+	}
+	if err := tx.Create(&order).Error; err != nil {
+		return err
+	}
+
+	for _, item := range fixture.Items {
+		menu := menus[item.Menu]
+		detail := models.OrderDetail{
+			OrderID:       order.ID,
+			MenuID:        menu.ID,
+			Quantity:      item.Quantity,
+			UnitPrice:     menu.Price,
+			SubtotalPrice: menu.Price * float64(item.Quantity),
+			Notes:         item.Notes,
+		}
+		if err := tx.Create(&detail).Error; err != nil {
+			return err
+		}
+	}
+
+	payment := models.Payment{
+		OrderID:              order.ID,
+		PaymentMethod:        method.Name,
+		PaymentStatus:        paymentStatus,
+		PaymentAccountNumber: method.AccountNumber,
+		PaymentAccountName:   method.AccountName,
+		PaymentCreateDate:    orderDate,
+		TransactionCode:      fixture.TransactionCode,
+	}
+	if paymentStatus == "success" {
+		payment.PaymentDate = orderDate
+	}
+	if err := tx.Create(&payment).Error; err != nil {
+		return err
+	}
+
+	result.Created++
+	return nil
+}
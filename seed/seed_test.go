@@ -0,0 +1,65 @@
+package seed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadDefaultFixtures(t *testing.T) {
+	fixtures, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(fixtures.Users) == 0 || len(fixtures.Menus) == 0 || len(fixtures.Orders) == 0 {
+		t.Errorf("default fixtures are missing data: %+v", fixtures)
+	}
+
+	codes := map[string]bool{}
+	for _, method := range fixtures.PaymentMethods {
+		codes[method.Code] = true
+	}
+	for _, code := range []string{"bca", "qris"} {
+		if !codes[code] {
+			t.Errorf("default fixtures are missing payment method %s", code)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	data := `{
+		"categories": [{"name": "Pasta"}],
+		"menus": [{"name": "Carbonara", "category": "Pasta", "price": 45000, "is_available": false}],
+		"payment_methods": [{"name": "QRIS", "code": "qris"}],
+		"orders": [{"transaction_code": "SEED-1", "payment_method": "qris", "items": [{"menu": "Carbonara", "quantity": 1}]}]
+	}`
+	fixtures, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if menu := fixtures.Menus[0]; menu.IsAvailable == nil || *menu.IsAvailable {
+		t.Errorf("is_available = %v, want false", menu.IsAvailable)
+	}
+}
+
+func TestParseRejectsInvalidFixtures(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown field", "menus:\n  - name: Carbonara\n    prize: 1\n", "field prize not found"},
+		{"unknown category", "menus:\n  - name: Carbonara\n    category: Pizza\n    price: 1\n", `unknown category "Pizza"`},
+		{"unknown menu", "payment_methods:\n  - {name: BCA, code: bca}\norders:\n  - transaction_code: SEED-1\n    payment_method: bca\n    items: [{menu: Lasagna, quantity: 1}]\n", `unknown menu "Lasagna"`},
+		{"bad date", "payment_methods:\n  - {name: BCA, code: bca}\norders:\n  - {transaction_code: SEED-1, payment_method: bca, order_date: yesterday}\n", "expected YYYY-MM-DD HH:MM:SS"},
+		{"user without password", "users:\n  - username: admin\n", "needs a username and password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
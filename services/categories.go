@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+)
+
+// CategoryService manages menu categories.
+type CategoryService struct {
+	store repositories.Store
+}
+
+func NewCategoryService(store repositories.Store) *CategoryService {
+	return &CategoryService{store: store}
+}
+
+func (s *CategoryService) List(ctx context.Context) ([]models.Category, error) {
+	return s.store.Categories().List(ctx)
+}
+
+func (s *CategoryService) Get(ctx context.Context, id int) (models.Category, error) {
+	category, err := s.store.Categories().FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return category, apperror.NotFound("Category not found")
+	}
+	return category, err
+}
+
+func (s *CategoryService) Create(ctx context.Context, category *models.Category) error {
+	return s.store.Categories().Create(ctx, category)
+}
+
+// Update replaces the fields of the category with id.
+func (s *CategoryService) Update(ctx context.Context, id int, category models.Category) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.store.Categories().Update(ctx, id, category)
+}
+
+// Patch changes only the fields set in patch on the category with id.
+func (s *CategoryService) Patch(ctx context.Context, id int, patch models.CategoryPatch) error {
+	category, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	patch.Apply(&category)
+	return s.store.Categories().Update(ctx, id, category)
+}
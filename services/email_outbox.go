@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/metrics"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/dimassfeb-09/pestapasta-be/tracing"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DispatcherOptions tune the email outbox dispatcher.
+type DispatcherOptions struct {
+	PollInterval time.Duration // How often to look for due emails
+	MaxAttempts  int           // Attempts before an email is dead-lettered
+	BaseBackoff  time.Duration // Delay after the first failure, doubled on each retry
+	MaxBackoff   time.Duration
+	Company      utils.Company // Printed on the receipts attached to invoice emails
+}
+
+var DefaultDispatcherOptions = DispatcherOptions{
+	PollInterval: 10 * time.Second,
+	MaxAttempts:  6,
+	BaseBackoff:  30 * time.Second,
+	MaxBackoff:   time.Hour,
+}
+
+// enqueueEmail stores an email for delivery. Pass the repository of the transaction that
+// writes the triggering change so the email exists if and only if that change is committed.
+func enqueueEmail(ctx context.Context, emails repositories.EmailRepository, email models.EmailOutbox) error {
+	now := time.Now()
+	email.ID = 0
+	email.Status = models.EmailStatusPending
+	email.Attempts = 0
+	email.NextAttemptAt = now
+	email.CreatedAt = now
+	return emails.CreateOutbox(ctx, &email)
+}
+
+// RunEmailDispatcher delivers due outbox emails until ctx is cancelled.
+func RunEmailDispatcher(ctx context.Context, db *gorm.DB, mailer helpers.Mailer, opts DispatcherOptions) {
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := DispatchNextEmail(db, mailer, opts)
+			if err != nil {
+				slog.ErrorContext(ctx, "Error dispatching email", "error", err)
+				break
+			}
+			if !sent || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchNextEmail attempts one due email. It reports false when nothing was due.
+// The row stays locked for the attempt, so several instances can dispatch concurrently.
+func DispatchNextEmail(db *gorm.DB, mailer helpers.Mailer, opts DispatcherOptions) (bool, error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	defer tx.Rollback()
+
+	var email models.EmailOutbox
+	query := tx
+	// SQLite used in tests has neither row locks nor concurrent dispatchers
+	if tx.Dialect().GetName() == "postgres" {
+		query = query.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED")
+	}
+	err := query.
+		Where("status IN (?) AND next_attempt_at <= ?", []string{models.EmailStatusPending, models.EmailStatusFailed}, time.Now()).
+		Order("next_attempt_at").
+		First(&email).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch outbox email: %w", err)
+	}
+
+	sendErr := deliverEmail(db, mailer, email, opts.Company)
+
+	now := time.Now()
+	status := models.EmailStatusFailed
+	updates := map[string]interface{}{"attempts": email.Attempts + 1}
+	switch {
+	case sendErr == nil:
+		status = models.EmailStatusSent
+		updates["sent_at"] = now
+		updates["last_error"] = ""
+	case email.Attempts+1 >= opts.MaxAttempts:
+		status = models.EmailStatusDead
+		updates["last_error"] = sendErr.Error()
+		slog.Error("Email dead-lettered", "email_id", email.ID, "recipient", email.Recipient, "attempts", email.Attempts+1, "error", sendErr)
+	default:
+		updates["last_error"] = sendErr.Error()
+		updates["next_attempt_at"] = now.Add(backoff(email.Attempts+1, opts))
+	}
+	updates["status"] = status
+
+	if err := tx.Model(&email).Updates(updates).Error; err != nil {
+		return false, fmt.Errorf("failed to update outbox email: %w", err)
+	}
+	metrics.EmailSends.WithLabelValues(email.Kind, status).Inc()
+	return true, tx.Commit().Error
+}
+
+// ResendEmail puts a failed or dead email back in the queue with a fresh attempt budget.
+func ResendEmail(db *gorm.DB, id int) error {
+	result := db.Model(&models.EmailOutbox{}).
+		Where("id = ? AND status IN (?)", id, []string{models.EmailStatusFailed, models.EmailStatusDead}).
+		Updates(map[string]interface{}{
+			"status":          models.EmailStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// deliverEmail sends one outbox email. Each attempt is its own trace, the dispatcher
+// polls too often to trace the lookups that find nothing.
+func deliverEmail(db *gorm.DB, mailer helpers.Mailer, email models.EmailOutbox, company utils.Company) (err error) {
+	ctx, span := tracing.Tracer().Start(context.Background(), "email.send", trace.WithAttributes(
+		attribute.Int("email.id", email.ID),
+		attribute.String("email.kind", email.Kind),
+		attribute.Int("email.attempt", email.Attempts+1),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	var attachments []helpers.Attachment
+	if email.Kind == models.EmailKindInvoice && email.OrderID != 0 {
+		var order models.Order
+		if err := tracing.WithContext(db, ctx).Preload("Payment").Preload("OrderDetails.Menu").First(&order, email.OrderID).Error; err != nil {
+			return fmt.Errorf("failed to load order %d: %w", email.OrderID, err)
+		}
+
+		// The receipt is a convenience, send the email without it rather than not at all
+		receipt, err := helpers.RenderReceiptPDF(helpers.ConvertOrderToInvoiceData(order, company))
+		if err != nil {
+			slog.ErrorContext(ctx, "Error rendering receipt", "order_id", order.ID, "error", err)
+		} else {
+			attachments = append(attachments, helpers.Attachment{
+				Filename:    "receipt-" + order.Payment.TransactionCode + ".pdf",
+				ContentType: "application/pdf",
+				Data:        receipt,
+			})
+		}
+	}
+
+	return mailer.Send(helpers.Message{
+		To:          email.Recipient,
+		ToName:      email.RecipientName,
+		Subject:     email.Subject,
+		HTML:        email.Body,
+		Text:        email.TextBody,
+		Attachments: attachments,
+	})
+}
+
+func backoff(attempts int, opts DispatcherOptions) time.Duration {
+	delay := opts.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= opts.MaxBackoff {
+			return opts.MaxBackoff
+		}
+	}
+	return delay
+}
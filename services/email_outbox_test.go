@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+func TestBackoff(t *testing.T) {
+	opts := DispatcherOptions{BaseBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, delay := range want {
+		if got := backoff(i+1, opts); got != delay {
+			t.Errorf("backoff after %d failures = %s, want %s", i+1, got, delay)
+		}
+	}
+	if got := backoff(100, opts); got != opts.MaxBackoff {
+		t.Errorf("backoff must stay capped without overflowing, got %s", got)
+	}
+}
+
+func TestDispatchNextEmail(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.DB().SetMaxOpenConns(1)
+	defer db.Close()
+	db.AutoMigrate(&models.EmailOutbox{})
+
+	if err := enqueueEmail(context.Background(), repositories.NewGormStore(db).Emails(), models.EmailOutbox{Kind: "status", Recipient: "budi@example.com", Subject: "Order ready"}); err != nil {
+		t.Fatalf("enqueueEmail: %v", err)
+	}
+
+	// Capturing into a path that is a file fails every send
+	broken := filepath.Join(t.TempDir(), "not-a-dir")
+	os.WriteFile(broken, nil, 0o644)
+	mailer := helpers.NewCaptureMailer(broken)
+	opts := DispatcherOptions{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour}
+
+	stored := func() models.EmailOutbox {
+		var email models.EmailOutbox
+		db.First(&email)
+		return email
+	}
+	dispatch := func() bool {
+		t.Helper()
+		sent, err := DispatchNextEmail(db, mailer, opts)
+		if err != nil {
+			t.Fatalf("DispatchNextEmail: %v", err)
+		}
+		return sent
+	}
+	makeDue := func() {
+		db.Model(&models.EmailOutbox{}).Update("next_attempt_at", time.Now().Add(-time.Second))
+	}
+
+	start := time.Now()
+	if !dispatch() {
+		t.Fatal("pending email not attempted")
+	}
+	email := stored()
+	if email.Status != models.EmailStatusFailed || email.Attempts != 1 || email.LastError == "" {
+		t.Fatalf("got %s after %d attempts (%q), want failed after 1", email.Status, email.Attempts, email.LastError)
+	}
+	if delay := email.NextAttemptAt.Sub(start); delay < time.Minute || delay > time.Minute+5*time.Second {
+		t.Errorf("got retry in %s, want the base backoff", delay)
+	}
+	if dispatch() {
+		t.Error("email retried before its backoff elapsed")
+	}
+
+	makeDue()
+	dispatch()
+	if email = stored(); email.Status != models.EmailStatusFailed || email.Attempts != 2 {
+		t.Fatalf("got %s after %d attempts, want failed after 2", email.Status, email.Attempts)
+	}
+	makeDue()
+	dispatch()
+	if email = stored(); email.Status != models.EmailStatusDead || email.Attempts != 3 {
+		t.Fatalf("got %s after %d attempts, want dead after MaxAttempts", email.Status, email.Attempts)
+	}
+	makeDue()
+	if dispatch() {
+		t.Error("dead email attempted again")
+	}
+
+	// A resend starts over and succeeds once the mailer works
+	if err := ResendEmail(db, email.ID); err != nil {
+		t.Fatalf("ResendEmail: %v", err)
+	}
+	mailer.Dir = ""
+	dispatch()
+	if email = stored(); email.Status != models.EmailStatusSent || email.Attempts != 1 || email.SentAt == nil || email.LastError != "" {
+		t.Errorf("got %+v, want sent on the first attempt after the resend", email)
+	}
+	if len(mailer.Messages()) != 4 {
+		t.Errorf("got %d captured messages, want one per attempt", len(mailer.Messages()))
+	}
+}
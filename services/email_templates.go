@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/jinzhu/gorm"
+)
+
+// ErrUnknownTemplate is returned for template names that have no built-in template.
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// TemplateError reports a template that fails to parse or render against the sample orders.
+type TemplateError struct {
+	Err error
+}
+
+func (e *TemplateError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// RenderEmailTemplate renders the stored version of a template in data.Language. When none
+// is stored, or it cannot be loaded or rendered, the built-in template is used so a bad
+// edit never stops customers from getting their emails.
+func RenderEmailTemplate(db *gorm.DB, name string, data helpers.InvoiceData) (helpers.RenderedEmail, error) {
+	return renderEmailTemplate(context.Background(), repositories.NewGormStore(db).Emails(), name, data)
+}
+
+func renderEmailTemplate(ctx context.Context, emails repositories.EmailRepository, name string, data helpers.InvoiceData) (helpers.RenderedEmail, error) {
+	data.Language = i18n.Resolve(data.Language)
+
+	stored, err := emails.ActiveTemplate(ctx, name, data.Language)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading email template, using built-in", "template", name, "error", err)
+	}
+	if stored != nil {
+		set := helpers.TemplateSet{Subject: stored.Subject, HTML: stored.HTML, Text: stored.Text}
+		rendered, err := helpers.RenderTemplateSet(name, set, data)
+		if err == nil {
+			return rendered, nil
+		}
+		slog.ErrorContext(ctx, "Error rendering email template, using built-in", "template", name, "version", stored.Version, "error", err)
+	}
+
+	return helpers.RenderEmail(name, data)
+}
+
+// SaveEmailTemplate validates set and stores it as the next version of the template.
+// Templates that fail validation are rejected with a *TemplateError.
+func SaveEmailTemplate(db *gorm.DB, name, lang string, set helpers.TemplateSet, createdBy string) (models.EmailTemplate, error) {
+	if _, ok := helpers.BuiltinTemplate(name); !ok {
+		return models.EmailTemplate{}, ErrUnknownTemplate
+	}
+	if err := helpers.ValidateTemplateSet(name, set, lang); err != nil {
+		return models.EmailTemplate{}, &TemplateError{Err: err}
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return models.EmailTemplate{}, tx.Error
+	}
+	defer tx.Rollback()
+
+	// Concurrent saves of the same template race for the same version and one
+	// fails on the unique index instead of silently overwriting the other
+	var latest struct{ Version int }
+	if err := tx.Model(&models.EmailTemplate{}).
+		Select("COALESCE(MAX(version), 0) AS version").
+		Where("name = ? AND language = ?", name, lang).
+		Scan(&latest).Error; err != nil {
+		return models.EmailTemplate{}, err
+	}
+
+	template := models.EmailTemplate{
+		Name:      name,
+		Language:  lang,
+		Version:   latest.Version + 1,
+		Subject:   set.Subject,
+		HTML:      set.HTML,
+		Text:      set.Text,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	if err := tx.Create(&template).Error; err != nil {
+		return models.EmailTemplate{}, err
+	}
+	return template, tx.Commit().Error
+}
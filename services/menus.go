@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+)
+
+// MenuService manages menus. Menus it returns have AvailableNow and EffectivePrice
+// evaluated from availability schedules and price rules.
+type MenuService struct {
+	store repositories.Store
+	Now   func() time.Time // Replaced in tests
+}
+
+func NewMenuService(store repositories.Store) *MenuService {
+	return &MenuService{store: store, Now: time.Now}
+}
+
+// List returns every menu, or those in the category with that name.
+func (s *MenuService) List(ctx context.Context, category string) ([]models.Menu, error) {
+	menus, err := s.store.Menus().List(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMenuSchedules(ctx, s.store, menus, s.Now()); err != nil {
+		return nil, err
+	}
+	return menus, nil
+}
+
+func (s *MenuService) Get(ctx context.Context, id int) (models.Menu, error) {
+	menu, err := s.store.Menus().FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return menu, apperror.NotFound("Product not found")
+	}
+	if err != nil {
+		return menu, err
+	}
+
+	menus := []models.Menu{menu}
+	if err := applyMenuSchedules(ctx, s.store, menus, s.Now()); err != nil {
+		return menu, err
+	}
+	return menus[0], nil
+}
+
+func (s *MenuService) Create(ctx context.Context, menu *models.Menu) error {
+	if err := s.checkCategory(ctx, menu.CategoryID); err != nil {
+		return err
+	}
+	return s.store.Menus().Create(ctx, menu)
+}
+
+// Update overwrites the editable fields of the menu with menu.ID.
+func (s *MenuService) Update(ctx context.Context, menu models.Menu) error {
+	if _, err := s.store.Menus().FindByID(ctx, menu.ID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return apperror.NotFound("Product not found")
+		}
+		return err
+	}
+	if err := s.checkCategory(ctx, menu.CategoryID); err != nil {
+		return err
+	}
+	return s.store.Menus().Update(ctx, menu)
+}
+
+// Patch changes only the fields set in patch on the menu with id.
+func (s *MenuService) Patch(ctx context.Context, id int, patch models.MenuPatch) error {
+	menu, err := s.store.Menus().FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return apperror.NotFound("Product not found")
+		}
+		return err
+	}
+	if patch.CategoryID != nil {
+		if err := s.checkCategory(ctx, *patch.CategoryID); err != nil {
+			return err
+		}
+	}
+
+	patch.Apply(&menu)
+	return s.store.Menus().Update(ctx, menu)
+}
+
+// checkCategory reports a category_id that does not refer to a category as an invalid field.
+func (s *MenuService) checkCategory(ctx context.Context, id int) error {
+	_, err := s.store.Categories().FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return apperror.InvalidField("category_id", "Category %d does not exist", id)
+	}
+	return err
+}
+
+// applyMenuSchedules evaluates availability schedules and price rules for the given menus at now.
+func applyMenuSchedules(ctx context.Context, store repositories.Store, menus []models.Menu, now time.Time) error {
+	schedules, err := store.Schedules().ListSchedules(ctx)
+	if err != nil {
+		return err
+	}
+	rules, err := store.Schedules().ListPriceRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	helpers.ApplySchedules(menus, schedules, rules, now)
+	return nil
+}
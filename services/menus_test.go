@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+)
+
+func TestMenuCategoryMustExist(t *testing.T) {
+	store := repositories.NewMemoryStore()
+	store.Seed(models.Category{ID: 10, CategoryName: "Pasta", Description: "Pasta"})
+	menus := NewMenuService(store)
+
+	err := menus.Create(context.Background(), &models.Menu{Name: "Carbonara", Price: 50000, CategoryID: 11})
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || len(appErr.Fields) != 1 || appErr.Fields[0].Name != "category_id" {
+		t.Fatalf("got %v, want an invalid category_id field", err)
+	}
+
+	menu := models.Menu{Name: "Carbonara", Price: 50000, CategoryID: 10}
+	if err := menus.Create(context.Background(), &menu); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	category := 11
+	if err := menus.Patch(context.Background(), menu.ID, models.MenuPatch{CategoryID: &category}); err == nil {
+		t.Errorf("Patch moved the menu to a missing category")
+	}
+}
+
+func TestMenuPatch(t *testing.T) {
+	store := repositories.NewMemoryStore()
+	store.Seed(models.Menu{ID: 1, Name: "Carbonara", Price: 50000, Description: "Creamy", CategoryID: 10, IsAvailable: true})
+	menus := NewMenuService(store)
+
+	price, available := 55000.0, false
+	if err := menus.Patch(context.Background(), 1, models.MenuPatch{Price: &price, IsAvailable: &available}); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	menu, err := menus.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if menu.Price != 55000 || menu.IsAvailable || menu.Name != "Carbonara" || menu.Description != "Creamy" || menu.CategoryID != 10 {
+		t.Errorf("got %+v, want only price and availability changed", menu)
+	}
+
+	if err := menus.Patch(context.Background(), 2, models.MenuPatch{Price: &price}); !errors.Is(err, apperror.ErrNotFound) {
+		t.Errorf("got %v for a missing menu, want ErrNotFound", err)
+	}
+}
@@ -2,27 +2,56 @@ package services
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/dimassfeb-09/pestapasta-be/logging"
+	"github.com/dimassfeb-09/pestapasta-be/metrics"
 	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/tracing"
 	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var (
-	env = utils.GetENV()
-)
+// PaymentGateway creates QRIS payments and reports their status. *MidtransClient implements it.
+type PaymentGateway interface {
+	CreateTransaction(ctx context.Context, trx models.CreateTransactionMidtransPayload) (*models.CreateTransactionMidtransResponse, *models.CreateTransactionMidtransResponseWithError, error)
+	CheckTransaction(ctx context.Context, transactionId string) (*models.StatusTransactionMidtransResponse, *models.CreateTransactionMidtransResponseWithError, error)
+}
 
-func CreateTransaction(trx models.CreateTransactionMidtransPayload) (*models.CreateTransactionMidtransResponse, *models.CreateTransactionMidtransResponseWithError, error) {
+// MidtransClient calls the Midtrans Core API with one server key.
+type MidtransClient struct {
+	ServerKey  string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+func NewMidtransClient(cfg utils.Midtrans) *MidtransClient {
+	return &MidtransClient{
+		ServerKey: cfg.ServerKey,
+		BaseURL:   cfg.BaseURL,
+		// The transport adds a span per HTTP call and sends the W3C traceparent header
+		HTTPClient: &http.Client{Timeout: 10 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+	}
+}
+
+func (m *MidtransClient) CreateTransaction(ctx context.Context, trx models.CreateTransactionMidtransPayload) (*models.CreateTransactionMidtransResponse, *models.CreateTransactionMidtransResponseWithError, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "midtrans.CreateTransaction")
+	start, statusCode := time.Now(), "error"
+	defer func() { observeMidtrans(span, "charge", statusCode, start) }()
 
 	// Create Additional Tax 10%
-	taxCount := trx.TransactionDetails.GrossAmount * 0.1
+	taxCount := trx.TransactionDetails.GrossAmount * models.TaxRate
 	trx.TransactionDetails.GrossAmount += taxCount
 	trx.ItemDetails = append(trx.ItemDetails, models.ItemDetails{
 		ID:       "Tax-10%",
@@ -37,25 +66,27 @@ func CreateTransaction(trx models.CreateTransactionMidtransPayload) (*models.Cre
 		return nil, nil, fmt.Errorf("failed to marshal transaction payload: %w", err)
 	}
 
-	fmt.Println(string(data))
+	// The payload carries customer details, only log what identifies the transaction
+	slog.DebugContext(ctx, "Creating Midtrans transaction",
+		"midtrans_order_id", trx.TransactionDetails.OrderID,
+		"gross_amount", trx.TransactionDetails.GrossAmount,
+		"items", len(trx.ItemDetails))
 
 	// Create request
 	bytesBuffer := bytes.NewBuffer(data)
 
-	req, err := http.NewRequest("POST", utils.EndpointMidtransCharge, bytesBuffer)
+	req, err := http.NewRequestWithContext(ctx, "POST", m.BaseURL+"/charge", bytesBuffer)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	// Add headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Basic "+basicAuth(utils.GetENV().MidtransKey, "")) // Replace with actual server key
-
-	// Configure HTTP client with timeout
-	client := &http.Client{Timeout: 10 * time.Second}
+	req.Header.Set("Authorization", "Basic "+basicAuth(m.ServerKey, ""))
+	setRequestID(req)
 
 	// Send the request
-	resp, err := client.Do(req)
+	resp, err := m.HTTPClient.Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
@@ -73,7 +104,10 @@ func CreateTransaction(trx models.CreateTransactionMidtransPayload) (*models.Cre
 		if err := json.Unmarshal(body, &errorResponse); err != nil {
 			return nil, nil, fmt.Errorf("failed to unmarshal error response: %w", err)
 		}
-		log.Printf("Error response: %v", errorResponse)
+		statusCode = errorResponse.StatusCode
+		slog.WarnContext(ctx, "Midtrans rejected the request",
+			"url", req.URL.Path, "http_status", resp.StatusCode,
+			"status_code", errorResponse.StatusCode, "status_message", errorResponse.StatusMessage)
 		return nil, &errorResponse, fmt.Errorf("failed to create transaction, status: %d, response: %v", resp.StatusCode, errorResponse)
 	}
 
@@ -82,33 +116,32 @@ func CreateTransaction(trx models.CreateTransactionMidtransPayload) (*models.Cre
 	if err := json.Unmarshal(body, &transactionResponse); err != nil {
 		return nil, nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	statusCode = transactionResponse.StatusCode
 
 	// Return success response
 	return &transactionResponse, nil, nil
 }
 
-func CheckTransaction(transactionId string) (*models.StatusTransactionMidtransResponse, *models.CreateTransactionMidtransResponseWithError, error) {
-	fmt.Println(transactionId)
-	url := fmt.Sprintf("https://api.midtrans.com/v2/%s/status", transactionId)
-	req, err := http.NewRequest("GET", url, nil)
+func (m *MidtransClient) CheckTransaction(ctx context.Context, transactionId string) (*models.StatusTransactionMidtransResponse, *models.CreateTransactionMidtransResponseWithError, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "midtrans.CheckTransaction")
+	start, statusCode := time.Now(), "error"
+	defer func() { observeMidtrans(span, "status", statusCode, start) }()
+
+	slog.DebugContext(ctx, "Checking Midtrans transaction", "transaction_id", transactionId)
+
+	url := fmt.Sprintf("%s/%s/status", m.BaseURL, transactionId)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	// Add headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Basic "+basicAuth(env.MidtransKey, "")) // Replace with actual server key
-
-	// Configure HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
+	req.Header.Set("Authorization", "Basic "+basicAuth(m.ServerKey, ""))
+	setRequestID(req)
 
 	// Send the request
-	resp, err := client.Do(req)
+	resp, err := m.HTTPClient.Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
@@ -126,7 +159,10 @@ func CheckTransaction(transactionId string) (*models.StatusTransactionMidtransRe
 		if err := json.Unmarshal(body, &errorResponse); err != nil {
 			return nil, nil, fmt.Errorf("failed to unmarshal error response: %w", err)
 		}
-		log.Printf("Error response: %v", errorResponse)
+		statusCode = errorResponse.StatusCode
+		slog.WarnContext(ctx, "Midtrans rejected the request",
+			"url", req.URL.Path, "http_status", resp.StatusCode,
+			"status_code", errorResponse.StatusCode, "status_message", errorResponse.StatusMessage)
 		return nil, &errorResponse, fmt.Errorf("failed to create transaction, status: %d, response: %v", resp.StatusCode, errorResponse)
 	}
 
@@ -135,11 +171,31 @@ func CheckTransaction(transactionId string) (*models.StatusTransactionMidtransRe
 	if err := json.Unmarshal(body, &statusTransactionResponse); err != nil {
 		return nil, nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	statusCode = statusTransactionResponse.StatusCode
 
 	// Return success response
 	return &statusTransactionResponse, nil, nil
 }
 
+// observeMidtrans records the duration of a call that started at start and ends its
+// span. statusCode is the status_code Midtrans answered with, or "error" without one.
+func observeMidtrans(span trace.Span, operation, statusCode string, start time.Time) {
+	metrics.ObserveMidtrans(operation, statusCode, start)
+	span.SetAttributes(attribute.String("midtrans.status_code", statusCode))
+	if !strings.HasPrefix(statusCode, "2") {
+		span.SetStatus(codes.Error, "midtrans status "+statusCode)
+	}
+	span.End()
+}
+
+// setRequestID forwards the request ID of req's context so a call can be matched with
+// Midtrans support.
+func setRequestID(req *http.Request) {
+	if id := logging.RequestID(req.Context()); id != "" {
+		req.Header.Set(logging.RequestIDHeader, id)
+	}
+}
+
 func basicAuth(username, password string) string {
 	auth := username + ":" + password
 	return base64.StdEncoding.EncodeToString([]byte(auth))
@@ -1,10 +1,20 @@
 package services
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/dimassfeb-09/pestapasta-be/logging"
 	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestCreateTransactionTest(t *testing.T) {
@@ -47,11 +57,77 @@ func TestCreateTransactionTest(t *testing.T) {
 			Acquirer: "gopay",
 		},
 	}
-	body, errCustom, err := CreateTransaction(transactionBody)
+
+	var charged models.CreateTransactionMidtransPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); r.URL.Path != "/charge" || !ok || user != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"status_code": "401", "status_message": "unauthorized"}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&charged)
+		w.Write([]byte(`{"status_code": "201", "transaction_id": "trx-1", "transaction_status": "pending", "actions": [{"name": "generate-qr-code", "url": "https://example.com/qr.png"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewMidtransClient(utils.Midtrans{BaseURL: srv.URL, ServerKey: "key"})
+	body, errCustom, err := client.CreateTransaction(context.Background(), transactionBody)
 	if err != nil || errCustom != nil {
-		log.Println(errCustom.StatusCode)
-		log.Fatal(err)
+		t.Fatalf("CreateTransaction: %v %+v", err, errCustom)
+	}
+	if body.TransactionID != "trx-1" || len(body.Actions) != 1 {
+		t.Errorf("got response %+v", body)
+	}
+
+	// Midtrans is charged the tax on top as an item of its own
+	if charged.TransactionDetails.GrossAmount != 22000 || len(charged.ItemDetails) != 3 || charged.ItemDetails[2].Price != 2000 {
+		t.Errorf("got payload %+v, want 22000 with a 2000 tax item", charged)
 	}
+}
+
+func TestMidtransPropagatesTraceAndRequestID(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Write([]byte(`{"status_code": "200", "transaction_status": "settlement"}`))
+	}))
+	defer srv.Close()
 
-	log.Println(body)
+	ctx, parent := otel.Tracer("test").Start(logging.WithRequestID(context.Background(), "req-1"), "handler")
+	client := NewMidtransClient(utils.Midtrans{BaseURL: srv.URL, ServerKey: "key"})
+	if _, _, err := client.CheckTransaction(ctx, "trx-1"); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	traceID := parent.SpanContext().TraceID().String()
+	if !strings.Contains(headers.Get("traceparent"), traceID) {
+		t.Errorf("traceparent %q does not continue trace %s", headers.Get("traceparent"), traceID)
+	}
+	if headers.Get(logging.RequestIDHeader) != "req-1" {
+		t.Errorf("request ID not forwarded: %q", headers.Get(logging.RequestIDHeader))
+	}
+
+	var found bool
+	for _, span := range recorder.Ended() {
+		if span.Name() != "midtrans.CheckTransaction" {
+			continue
+		}
+		found = true
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Midtrans span is not a child of the handler span")
+		}
+		for _, attr := range span.Attributes() {
+			if attr.Key == "midtrans.status_code" && attr.Value.AsString() != "200" {
+				t.Errorf("got status code %s, want 200", attr.Value.AsString())
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no midtrans.CheckTransaction span recorded")
+	}
 }
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+)
+
+// statusTemplates maps the status an order enters to the email the customer receives.
+var statusTemplates = map[string]string{
+	"success":                        helpers.TemplatePaymentReceived,
+	"captured":                       helpers.TemplatePaymentReceived,
+	"expired":                        helpers.TemplatePaymentExpired,
+	models.OrderStatusReadyForPickup: helpers.TemplateReadyForPickup,
+	"canceled":                       helpers.TemplateOrderCancelled,
+	"refunded":                       helpers.TemplateRefundIssued,
+	"partially_refunded":             helpers.TemplateRefundIssued,
+}
+
+// notifyOrderStatusChange queues the lifecycle email for an order that just entered status.
+// Statuses without a template are ignored. The order must be loaded with Payment and
+// OrderDetails.Menu as it was before the change.
+func notifyOrderStatusChange(ctx context.Context, emails repositories.EmailRepository, order models.Order, status string, company utils.Company) error {
+	name, ok := statusTemplates[status]
+	if !ok {
+		return nil
+	}
+	// A capture that settles later is still one payment, announce it once
+	if name == helpers.TemplatePaymentReceived && models.IsPaidStatus(order.Payment.PaymentStatus) {
+		return nil
+	}
+	return queueOrderEmail(ctx, emails, name, order, company)
+}
+
+// queueOrderEmail renders the template name for order and queues it for the customer.
+func queueOrderEmail(ctx context.Context, emails repositories.EmailRepository, name string, order models.Order, company utils.Company) error {
+	rendered, err := renderEmailTemplate(ctx, emails, name, helpers.ConvertOrderToInvoiceData(order, company))
+	if err != nil {
+		return err
+	}
+
+	return enqueueEmail(ctx, emails, models.EmailOutbox{
+		Kind:          name,
+		OrderID:       order.ID,
+		Recipient:     order.Email,
+		RecipientName: order.Name,
+		Subject:       rendered.Subject,
+		Body:          rendered.HTML,
+		TextBody:      rendered.Text,
+	})
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/metrics"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+)
+
+// OrderService places orders and moves them through their lifecycle.
+type OrderService struct {
+	store   repositories.Store
+	gateway PaymentGateway
+	company utils.Company
+	Now     func() time.Time // Replaced in tests
+}
+
+func NewOrderService(store repositories.Store, gateway PaymentGateway, company utils.Company) *OrderService {
+	return &OrderService{store: store, gateway: gateway, company: company, Now: time.Now}
+}
+
+func (s *OrderService) List(ctx context.Context, filter repositories.OrderFilter) ([]models.Order, error) {
+	return s.store.Orders().List(ctx, filter)
+}
+
+func (s *OrderService) Get(ctx context.Context, id int) (models.Order, error) {
+	order, err := s.store.Orders().FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return order, apperror.NotFound("Order not found")
+	}
+	return order, err
+}
+
+func (s *OrderService) GetByTransactionCode(ctx context.Context, transactionCode string) (models.Order, error) {
+	order, err := s.store.Orders().FindByTransactionCode(ctx, transactionCode)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return order, apperror.NotFound("Order not found")
+	}
+	return order, err
+}
+
+// Checkout prices the requested menus with the schedules and price rules in effect now,
+// creates the QRIS payment at Midtrans when that method is chosen, and stores the order,
+// its lines, payment and invoice email in one transaction. request.Language must already
+// be resolved. The returned order includes its Payment and OrderDetails.
+func (s *OrderService) Checkout(ctx context.Context, request models.CheckoutRequest) (models.Order, error) {
+	order, paymentMethod, err := s.checkout(ctx, request)
+	metrics.Checkouts.WithLabelValues(paymentMethod, checkoutOutcome(err)).Inc()
+	return order, err
+}
+
+func checkoutOutcome(err error) string {
+	var appErr *apperror.Error
+	switch {
+	case err == nil:
+		return metrics.OutcomeSuccess
+	case errors.Is(err, apperror.ErrPaymentFailed):
+		return metrics.OutcomePaymentFailed
+	case errors.As(err, &appErr) && appErr.Status < 500:
+		return metrics.OutcomeRejected
+	}
+	return metrics.OutcomeError
+}
+
+// checkout implements Checkout and also returns the payment method code, or "unknown"
+// when the request failed before the method was looked up.
+func (s *OrderService) checkout(ctx context.Context, request models.CheckoutRequest) (models.Order, string, error) {
+	methodCode := "unknown"
+
+	// Validate input
+	for _, item := range request.Products {
+		if item.Quantity <= 0 {
+			return models.Order{}, methodCode, apperror.Invalid("Quantity must be greater than 0")
+		}
+	}
+
+	productIDs := make([]int, len(request.Products))
+	for i, item := range request.Products {
+		productIDs[i] = item.ID
+	}
+	products, err := s.store.Menus().FindByIDs(ctx, productIDs)
+	if err != nil {
+		return models.Order{}, methodCode, fmt.Errorf("failed to fetch products: %w", err)
+	}
+
+	// Check if all requested products exist in the fetched products
+	productMap := make(map[int]models.Menu)
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+	for _, item := range request.Products {
+		if _, exists := productMap[item.ID]; !exists {
+			return models.Order{}, methodCode, apperror.NotFound("Product with ID %d not found", item.ID)
+		}
+	}
+
+	// Evaluate schedules and price rules once so every line uses the same moment
+	now := s.Now()
+	if err := applyMenuSchedules(ctx, s.store, products, now); err != nil {
+		return models.Order{}, methodCode, fmt.Errorf("failed to evaluate menu schedules: %w", err)
+	}
+	for i, product := range products {
+		if !product.AvailableNow {
+			return models.Order{}, methodCode, apperror.Unprocessable("Product %s is not available at this time", product.Name)
+		}
+		productMap[product.ID] = products[i]
+	}
+
+	// Map quantities and notes from request to products
+	productQuantities := make(map[int]int)
+	productNotes := make(map[int]string)
+	for _, item := range request.Products {
+		productQuantities[item.ID] = item.Quantity
+		productNotes[item.ID] = item.Notes
+	}
+
+	// Calculate total price
+	total := 0.0
+	discount := 0.0
+	for _, product := range products {
+		quantity := productQuantities[product.ID]
+		total += product.EffectivePrice * float64(quantity)
+		discount += (product.Price - product.EffectivePrice) * float64(quantity)
+	}
+
+	order := models.Order{
+		OrderDate:   now.UTC().Format("2006-01-02 15:04:05"),
+		TotalPrice:  total,
+		Discount:    discount,
+		Tax:         total * models.TaxRate,
+		OrderStatus: "Pending",
+		Email:       request.Email,
+		Name:        request.Name,
+		Language:    request.Language,
+	}
+	for _, product := range products {
+		quantity := productQuantities[product.ID]
+		order.OrderDetails = append(order.OrderDetails, models.OrderDetail{
+			MenuID:        product.ID,
+			Quantity:      quantity,
+			UnitPrice:     product.EffectivePrice,
+			Discount:      (product.Price - product.EffectivePrice) * float64(quantity),
+			Notes:         productNotes[product.ID],
+			SubtotalPrice: product.EffectivePrice * float64(quantity),
+			Menu:          product,
+		})
+	}
+
+	paymentMethod, err := s.store.PaymentMethods().FindByID(ctx, request.PaymentMethodID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return models.Order{}, methodCode, apperror.NotFound("Payment method not found")
+	}
+	if err != nil {
+		return models.Order{}, methodCode, fmt.Errorf("failed to fetch payment method: %w", err)
+	}
+	methodCode = paymentMethod.Code
+
+	var midtransResponse *models.CreateTransactionMidtransResponse
+	if paymentMethod.Code == "qris" {
+		if midtransResponse, err = s.createQRISTransaction(ctx, request, productMap, total); err != nil {
+			return models.Order{}, methodCode, err
+		}
+	}
+
+	err = s.store.Transaction(ctx, func(tx repositories.Store) error {
+		if err := tx.Orders().Create(ctx, &order); err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		payment := models.Payment{
+			OrderID:              order.ID,
+			PaymentMethod:        paymentMethod.Name,
+			PaymentAccountNumber: paymentMethod.AccountNumber,
+			PaymentAccountName:   paymentMethod.AccountName,
+			PaymentStatus:        "pending",
+			PaymentCreateDate:    now.UTC().Format("2006-01-02 15:04:05"),
+			TransactionCode:      fmt.Sprintf("TXN%d", order.ID),
+		}
+		if payment.PaymentMethod == "QRIS" && midtransResponse != nil {
+			if len(midtransResponse.Actions) > 0 {
+				payment.PaymentQRCodeURL = midtransResponse.Actions[0].URL
+			}
+			payment.PaymentTransactionID = midtransResponse.TransactionID
+			payment.PaymentExpiredDate = midtransExpiry(midtransResponse.ExpiryTime)
+			payment.PaymentQRString = midtransResponse.QRString
+		}
+		if err := tx.Payments().Create(ctx, &payment); err != nil {
+			return fmt.Errorf("failed to create payment: %w", err)
+		}
+		order.Payment = payment
+
+		// Queue the invoice email, the dispatcher attaches the PDF receipt when sending
+		if err := queueOrderEmail(ctx, tx.Emails(), helpers.TemplateInvoice, order, s.company); err != nil {
+			return fmt.Errorf("failed to queue invoice email: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Order{}, methodCode, err
+	}
+	return order, methodCode, nil
+}
+
+func (s *OrderService) createQRISTransaction(ctx context.Context, request models.CheckoutRequest, productMap map[int]models.Menu, total float64) (*models.CreateTransactionMidtransResponse, error) {
+	var itemDetails []models.ItemDetails
+	for _, item := range request.Products {
+		product := productMap[item.ID]
+		itemDetails = append(itemDetails, models.ItemDetails{
+			ID:       fmt.Sprintf("PRODUCTID-%d", product.ID),
+			Price:    product.EffectivePrice,
+			Quantity: item.Quantity,
+			Name:     product.Name,
+		})
+	}
+
+	payload := models.CreateTransactionMidtransPayload{
+		PaymentType: "qris",
+		ItemDetails: itemDetails,
+	}
+	payload.TransactionDetails.OrderID = fmt.Sprintf("ORDER-%d", s.Now().Unix()) // Generate dynamic OrderID
+	payload.TransactionDetails.GrossAmount = total
+	payload.CustomerDetails.FirstName = request.Name
+	payload.CustomerDetails.LastName = request.Name
+	payload.CustomerDetails.Email = request.Email
+	payload.QRIS.Acquirer = "gopay"
+
+	response, errorResponse, err := s.gateway.CreateTransaction(ctx, payload)
+	if errorResponse != nil || err != nil {
+		var status int
+		if errorResponse != nil {
+			status, _ = strconv.Atoi(errorResponse.StatusCode)
+		}
+		if err == nil {
+			err = fmt.Errorf("midtrans rejected the transaction: %s", errorResponse.StatusMessage)
+		}
+		return nil, apperror.Payment(status, err)
+	}
+	return response, nil
+}
+
+// UpdateOrderStatus lets staff move an order through its lifecycle and queues the email
+// for the customer. status must be one of success, ready_for_pickup, completed, canceled
+// or refunded.
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, id int, status string) error {
+	order, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	paid := models.IsPaidStatus(order.OrderStatus)
+	pending := strings.EqualFold(order.OrderStatus, "pending")
+
+	// Payment status only changes when money moves; fulfilment steps keep it as is
+	paymentStatus := order.Payment.PaymentStatus
+	switch status {
+	case "success":
+		if !pending {
+			return apperror.Conflict("Only pending orders can be marked as paid")
+		}
+		paymentStatus = "success"
+	case models.OrderStatusReadyForPickup, models.OrderStatusCompleted:
+		if !paid {
+			return apperror.Conflict("Order has not been paid")
+		}
+	case "canceled":
+		if !pending {
+			return apperror.Conflict("Only pending orders can be cancelled, refund paid orders instead")
+		}
+		paymentStatus = "canceled"
+	case "refunded":
+		if !paid {
+			return apperror.Conflict("Only paid orders can be refunded")
+		}
+		paymentStatus = "refunded"
+	}
+
+	if order.OrderStatus == status {
+		return apperror.Conflict("Order already has this status")
+	}
+
+	return s.store.Transaction(ctx, func(tx repositories.Store) error {
+		if err := tx.Orders().UpdateStatus(ctx, order.ID, status); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		if err := tx.Payments().UpdateStatusByOrderID(ctx, order.ID, paymentStatus); err != nil {
+			return fmt.Errorf("failed to update payment status: %w", err)
+		}
+		if err := notifyOrderStatusChange(ctx, tx.Emails(), order, status, s.company); err != nil {
+			return fmt.Errorf("failed to queue status email: %w", err)
+		}
+		return nil
+	})
+}
+
+// midtransExpiry converts an expiry time Midtrans reports in WIB to the UTC the other
+// payment timestamps are stored in, keeping values it can't parse as they are.
+func midtransExpiry(expiry string) string {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", expiry, helpers.Jakarta)
+	if err != nil {
+		return expiry
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
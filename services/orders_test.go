@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/metrics"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeGateway records the calls a service makes to Midtrans.
+type fakeGateway struct {
+	created  int
+	checked  []string
+	create   *models.CreateTransactionMidtransResponseWithError
+	statuses map[string]string
+}
+
+func (g *fakeGateway) CreateTransaction(ctx context.Context, trx models.CreateTransactionMidtransPayload) (*models.CreateTransactionMidtransResponse, *models.CreateTransactionMidtransResponseWithError, error) {
+	g.created++
+	if g.create != nil {
+		return nil, g.create, nil
+	}
+	return &models.CreateTransactionMidtransResponse{
+		TransactionID: "trx-1",
+		QRString:      "qr",
+		ExpiryTime:    "2024-12-23 16:15:00",
+		Actions:       []models.Action{{URL: "https://example.com/qr.png"}},
+	}, nil, nil
+}
+
+func (g *fakeGateway) CheckTransaction(ctx context.Context, transactionId string) (*models.StatusTransactionMidtransResponse, *models.CreateTransactionMidtransResponseWithError, error) {
+	g.checked = append(g.checked, transactionId)
+	return &models.StatusTransactionMidtransResponse{TransactionStatus: g.statuses[transactionId]}, nil, nil
+}
+
+func newTestOrderService() (*OrderService, *repositories.MemoryStore, *fakeGateway) {
+	store := repositories.NewMemoryStore()
+	store.Seed(
+		models.Menu{ID: 1, Name: "Carbonara", Price: 50000, CategoryID: 10, IsAvailable: true},
+		models.Menu{ID: 2, Name: "Lasagna", Price: 60000, CategoryID: 10, IsAvailable: false},
+		models.PaymentMethod{ID: 20, Name: "BCA", Code: "bca"},
+		models.PaymentMethod{ID: 21, Name: "QRIS", Code: "qris"},
+		models.PriceRule{MenuID: 1, DiscountPercent: 20},
+	)
+	gateway := &fakeGateway{}
+	orders := NewOrderService(store, gateway, utils.Company{Name: "Pesta Pasta"})
+	orders.Now = func() time.Time { return time.Date(2024, 12, 23, 9, 0, 0, 0, time.UTC) }
+	return orders, store, gateway
+}
+
+func TestCheckout(t *testing.T) {
+	orders, store, gateway := newTestOrderService()
+	// Reports read order_date as UTC whatever the zone of the server
+	orders.Now = func() time.Time {
+		return time.Date(2024, 12, 23, 16, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	}
+
+	order, err := orders.Checkout(context.Background(), models.CheckoutRequest{
+		Name:            "Budi",
+		Email:           "budi@example.com",
+		PaymentMethodID: 20,
+		Language:        "en",
+		Products:        []models.CheckoutItem{{ID: 1, Quantity: 2, Notes: "extra cheese"}},
+	})
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if order.TotalPrice != 80000 || order.Discount != 20000 || order.Tax != 8000 {
+		t.Errorf("got total=%v discount=%v tax=%v, want 80000 20000 8000", order.TotalPrice, order.Discount, order.Tax)
+	}
+	if order.OrderDate != "2024-12-23 09:00:00" || order.Payment.PaymentCreateDate != "2024-12-23 09:00:00" {
+		t.Errorf("got order date %q, payment date %q, want both in UTC", order.OrderDate, order.Payment.PaymentCreateDate)
+	}
+	if order.Payment.TransactionCode == "" || order.Payment.PaymentStatus != "pending" {
+		t.Errorf("unexpected payment %+v", order.Payment)
+	}
+	if gateway.created != 0 {
+		t.Errorf("bank transfer must not call Midtrans")
+	}
+
+	stored, err := orders.GetByTransactionCode(context.Background(), order.Payment.TransactionCode)
+	if err != nil {
+		t.Fatalf("GetByTransactionCode: %v", err)
+	}
+	if len(stored.OrderDetails) != 1 || stored.OrderDetails[0].UnitPrice != 40000 || stored.OrderDetails[0].Notes != "extra cheese" {
+		t.Errorf("unexpected order details %+v", stored.OrderDetails)
+	}
+
+	outbox := store.Outbox()
+	if len(outbox) != 1 || outbox[0].Kind != helpers.TemplateInvoice || outbox[0].Recipient != "budi@example.com" {
+		t.Errorf("invoice email not queued: %+v", outbox)
+	}
+}
+
+func TestCheckoutQRIS(t *testing.T) {
+	orders, store, gateway := newTestOrderService()
+	request := models.CheckoutRequest{PaymentMethodID: 21, Language: "en", Products: []models.CheckoutItem{{ID: 1, Quantity: 1}}}
+
+	order, err := orders.Checkout(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if gateway.created != 1 || order.Payment.PaymentTransactionID != "trx-1" || order.Payment.PaymentQRCodeURL == "" {
+		t.Errorf("QRIS payment not created: %+v", order.Payment)
+	}
+	if order.Payment.PaymentExpiredDate != "2024-12-23 09:15:00" {
+		t.Errorf("got expiry %q, want the WIB time from Midtrans in UTC", order.Payment.PaymentExpiredDate)
+	}
+
+	failures := metrics.Checkouts.WithLabelValues("qris", metrics.OutcomePaymentFailed)
+	before := testutil.ToFloat64(failures)
+	gateway.create = &models.CreateTransactionMidtransResponseWithError{StatusCode: "406", StatusMessage: "duplicate"}
+	_, err = orders.Checkout(context.Background(), request)
+	var paymentErr *apperror.Error
+	if !errors.As(err, &paymentErr) || paymentErr.Code != apperror.CodePaymentFailed || paymentErr.Status != 406 {
+		t.Fatalf("got %v, want a payment error with status 406", err)
+	}
+	if testutil.ToFloat64(failures) != before+1 {
+		t.Errorf("failed QRIS checkout not counted")
+	}
+	if list, _ := orders.List(context.Background(), repositories.OrderFilter{}); len(list) != 1 {
+		t.Errorf("failed checkout stored an order, have %d", len(list))
+	}
+	if len(store.Outbox()) != 1 {
+		t.Errorf("failed checkout queued an email")
+	}
+}
+
+func TestCheckoutRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		request models.CheckoutRequest
+		want    error
+	}{
+		{"zero quantity", models.CheckoutRequest{PaymentMethodID: 20, Products: []models.CheckoutItem{{ID: 1, Quantity: 0}}}, apperror.ErrInvalid},
+		{"unknown product", models.CheckoutRequest{PaymentMethodID: 20, Products: []models.CheckoutItem{{ID: 99, Quantity: 1}}}, apperror.ErrNotFound},
+		{"unavailable product", models.CheckoutRequest{PaymentMethodID: 20, Products: []models.CheckoutItem{{ID: 2, Quantity: 1}}}, apperror.ErrUnprocessable},
+		{"unknown payment method", models.CheckoutRequest{PaymentMethodID: 99, Products: []models.CheckoutItem{{ID: 1, Quantity: 1}}}, apperror.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, _, _ := newTestOrderService()
+			if _, err := orders.Checkout(context.Background(), tt.request); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	orders, store, _ := newTestOrderService()
+	ctx := context.Background()
+	order, err := orders.Checkout(ctx, models.CheckoutRequest{PaymentMethodID: 20, Language: "en", Products: []models.CheckoutItem{{ID: 1, Quantity: 1}}})
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	if err := orders.UpdateOrderStatus(ctx, order.ID, models.OrderStatusReadyForPickup); !errors.Is(err, apperror.ErrConflict) {
+		t.Errorf("unpaid order marked ready: %v", err)
+	}
+	if err := orders.UpdateOrderStatus(ctx, order.ID, "success"); err != nil {
+		t.Fatalf("mark paid: %v", err)
+	}
+	if err := orders.UpdateOrderStatus(ctx, order.ID, "canceled"); !errors.Is(err, apperror.ErrConflict) {
+		t.Errorf("paid order cancelled: %v", err)
+	}
+	if err := orders.UpdateOrderStatus(ctx, 999, "success"); !errors.Is(err, apperror.ErrNotFound) {
+		t.Errorf("got %v for a missing order, want apperror.ErrNotFound", err)
+	}
+
+	stored, _ := orders.Get(ctx, order.ID)
+	if stored.OrderStatus != "success" || stored.Payment.PaymentStatus != "success" {
+		t.Errorf("got order %q payment %q, want success", stored.OrderStatus, stored.Payment.PaymentStatus)
+	}
+	outbox := store.Outbox()
+	if len(outbox) != 2 || outbox[1].Kind != helpers.TemplatePaymentReceived {
+		t.Errorf("payment email not queued: %+v", outbox)
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+// PaymentService lists payment methods and keeps payment statuses in sync with Midtrans.
+type PaymentService struct {
+	store   repositories.Store
+	gateway PaymentGateway
+	company utils.Company
+
+	refreshInterval time.Duration
+	checks          singleflight.Group
+	now             func() time.Time
+}
+
+// NewPaymentService asks Midtrans for a pending payment status at most once every
+// refreshInterval.
+func NewPaymentService(store repositories.Store, gateway PaymentGateway, company utils.Company, refreshInterval time.Duration) *PaymentService {
+	return &PaymentService{store: store, gateway: gateway, company: company, refreshInterval: refreshInterval, now: time.Now}
+}
+
+func (s *PaymentService) ListMethods(ctx context.Context) ([]models.PaymentMethod, error) {
+	return s.store.PaymentMethods().List(ctx)
+}
+
+// midtransStatuses maps Midtrans transaction statuses to payment statuses.
+var midtransStatuses = map[string]string{
+	"authorize":          "authorized",
+	"capture":            "captured",
+	"settlement":         "success",
+	"deny":               "denied",
+	"pending":            "pending",
+	"cancel":             "canceled",
+	"refund":             "refunded",
+	"partial_refund":     "partially_refunded",
+	"chargeback":         "charged_back",
+	"partial_chargeback": "partially_charged_back",
+	"expire":             "expired",
+	"failure":            "failed",
+}
+
+// PaymentStatusFromMidtrans maps a Midtrans transaction status to a payment status.
+func PaymentStatusFromMidtrans(transactionStatus string) string {
+	if status, ok := midtransStatuses[transactionStatus]; ok {
+		return status
+	}
+	return "unknown"
+}
+
+// finalPaymentStatuses never change again on the Midtrans side without staff starting
+// a refund, so they are not checked anymore.
+var finalPaymentStatuses = map[string]bool{
+	"success":      true,
+	"denied":       true,
+	"canceled":     true,
+	"refunded":     true,
+	"charged_back": true,
+	"expired":      true,
+	"failed":       true,
+}
+
+// RefreshOrderStatus asks Midtrans for the payment status of an order, stores it and
+// emails the customer when it changed. It returns the order status afterwards.
+//
+// Final statuses, orders paid without Midtrans (bank transfers) and statuses checked
+// within the refresh interval come from the database. Concurrent refreshes of one
+// order share a single Midtrans call.
+func (s *PaymentService) RefreshOrderStatus(ctx context.Context, orderID int) (string, error) {
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	if !s.needsCheck(order.Payment) {
+		return order.OrderStatus, nil
+	}
+
+	// The first caller's cancellation must not fail the others waiting on its call
+	ctx = context.WithoutCancel(ctx)
+	status, err, _ := s.checks.Do(strconv.Itoa(orderID), func() (interface{}, error) {
+		return s.checkOrderStatus(ctx, orderID)
+	})
+	if err != nil {
+		return "", err
+	}
+	return status.(string), nil
+}
+
+func (s *PaymentService) findOrder(ctx context.Context, orderID int) (models.Order, error) {
+	order, err := s.store.Orders().FindByID(ctx, orderID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return order, apperror.NotFound("Order not found")
+	}
+	if err != nil {
+		return order, fmt.Errorf("failed to fetch order: %w", err)
+	}
+	return order, nil
+}
+
+// needsCheck reports whether Midtrans may know a newer status than payment.
+func (s *PaymentService) needsCheck(payment models.Payment) bool {
+	if payment.PaymentTransactionID == "" || finalPaymentStatuses[payment.PaymentStatus] {
+		return false
+	}
+	return payment.StatusCheckedAt == nil || s.now().Sub(*payment.StatusCheckedAt) >= s.refreshInterval
+}
+
+func (s *PaymentService) checkOrderStatus(ctx context.Context, orderID int) (string, error) {
+	// Read again, a check that just finished may have made this one unnecessary
+	order, err := s.findOrder(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	if !s.needsCheck(order.Payment) {
+		return order.OrderStatus, nil
+	}
+
+	// Periksa transaksi menggunakan layanan eksternal
+	result, errResp, errCheckTrx := s.gateway.CheckTransaction(ctx, order.Payment.PaymentTransactionID)
+	if errResp != nil || errCheckTrx != nil {
+		return "", fmt.Errorf("error checking transaction: %v %v", errResp, errCheckTrx)
+	}
+	paymentStatus := PaymentStatusFromMidtrans(result.TransactionStatus)
+	checkedAt := s.now()
+
+	// Status tidak berubah, cukup catat waktu pengecekan tanpa email
+	if order.Payment.PaymentStatus == paymentStatus {
+		if err := s.store.Payments().RecordStatusCheck(ctx, orderID, paymentStatus, checkedAt); err != nil {
+			return "", fmt.Errorf("failed to record status check: %w", err)
+		}
+		return order.OrderStatus, nil
+	}
+
+	// Jangan timpa status yang diset staff (mis. ready_for_pickup) dengan status pembayaran yang sama-sama lunas
+	orderStatus := paymentStatus
+	if models.IsPaidStatus(paymentStatus) && models.IsPaidStatus(order.OrderStatus) {
+		orderStatus = order.OrderStatus
+	}
+
+	err = s.store.Transaction(ctx, func(tx repositories.Store) error {
+		if err := tx.Orders().UpdateStatus(ctx, orderID, orderStatus); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		if err := tx.Payments().RecordStatusCheck(ctx, orderID, paymentStatus, checkedAt); err != nil {
+			return fmt.Errorf("failed to update payment status: %w", err)
+		}
+		// Kirim email sesuai perubahan status pembayaran
+		if err := notifyOrderStatusChange(ctx, tx.Emails(), order, paymentStatus, s.company); err != nil {
+			return fmt.Errorf("failed to queue status email: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return orderStatus, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/pestapasta-be/helpers"
+	"github.com/dimassfeb-09/pestapasta-be/models"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+)
+
+func TestRefreshOrderStatus(t *testing.T) {
+	orders, store, gateway := newTestOrderService()
+	payments := NewPaymentService(store, gateway, utils.Company{}, 0)
+	ctx := context.Background()
+
+	order, err := orders.Checkout(ctx, models.CheckoutRequest{PaymentMethodID: 21, Language: "en", Products: []models.CheckoutItem{{ID: 1, Quantity: 1}}})
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	gateway.statuses = map[string]string{"trx-1": "capture"}
+	status, err := payments.RefreshOrderStatus(ctx, order.ID)
+	if err != nil || status != "captured" {
+		t.Fatalf("got %q, %v, want captured", status, err)
+	}
+	if err := orders.UpdateOrderStatus(ctx, order.ID, models.OrderStatusReadyForPickup); err != nil {
+		t.Fatalf("mark ready: %v", err)
+	}
+
+	// An unchanged payment status is a no-op
+	emails := len(store.Outbox())
+	if status, err = payments.RefreshOrderStatus(ctx, order.ID); err != nil || status != models.OrderStatusReadyForPickup {
+		t.Errorf("got %q, %v, want %s", status, err, models.OrderStatusReadyForPickup)
+	}
+	if len(store.Outbox()) != emails {
+		t.Errorf("unchanged payment status queued an email")
+	}
+
+	// Another paid status from Midtrans must not undo staff progress
+	gateway.statuses["trx-1"] = "settlement"
+	if status, err = payments.RefreshOrderStatus(ctx, order.ID); err != nil || status != models.OrderStatusReadyForPickup {
+		t.Errorf("got %q, %v, want %s to be kept", status, err, models.OrderStatusReadyForPickup)
+	}
+	stored, _ := orders.Get(ctx, order.ID)
+	if stored.Payment.PaymentStatus != "success" || stored.Payment.StatusCheckedAt == nil {
+		t.Errorf("got payment %+v, want a checked success", stored.Payment)
+	}
+}
+
+func TestRefreshOrderStatusAvoidsMidtrans(t *testing.T) {
+	orders, store, gateway := newTestOrderService()
+	payments := NewPaymentService(store, gateway, utils.Company{}, time.Minute)
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	payments.now = func() time.Time { return now }
+	ctx := context.Background()
+	checkout := func(paymentMethodID int) models.Order {
+		order, err := orders.Checkout(ctx, models.CheckoutRequest{PaymentMethodID: paymentMethodID, Language: "en", Products: []models.CheckoutItem{{ID: 1, Quantity: 1}}})
+		if err != nil {
+			t.Fatalf("Checkout: %v", err)
+		}
+		return order
+	}
+	gateway.statuses = map[string]string{"trx-1": "pending"}
+
+	// Bank transfers have no Midtrans transaction to check
+	transfer := checkout(20)
+	if _, err := payments.RefreshOrderStatus(ctx, transfer.ID); err != nil || len(gateway.checked) != 0 {
+		t.Fatalf("bank transfer checked with Midtrans: %v %v", gateway.checked, err)
+	}
+
+	qris := checkout(21)
+	if _, err := payments.RefreshOrderStatus(ctx, qris.ID); err != nil || len(gateway.checked) != 1 {
+		t.Fatalf("got %d checks, %v, want the first read checked", len(gateway.checked), err)
+	}
+	now = now.Add(30 * time.Second)
+	if _, err := payments.RefreshOrderStatus(ctx, qris.ID); err != nil || len(gateway.checked) != 1 {
+		t.Errorf("got %d checks, %v, want the status served within the interval", len(gateway.checked), err)
+	}
+
+	// Concurrent reads after the interval share one call
+	now = now.Add(time.Minute)
+	gateway.statuses["trx-1"] = "expire"
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status, err := payments.RefreshOrderStatus(ctx, qris.ID); err != nil || status != "expired" {
+				t.Errorf("got %q, %v, want expired", status, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(gateway.checked) != 2 {
+		t.Errorf("got %d checks, want concurrent reads to share one", len(gateway.checked))
+	}
+
+	// Final statuses are never checked again
+	now = now.Add(time.Hour)
+	if status, err := payments.RefreshOrderStatus(ctx, qris.ID); err != nil || status != "expired" || len(gateway.checked) != 2 {
+		t.Errorf("got %q, %v after %d checks, want expired from the database", status, err, len(gateway.checked))
+	}
+}
+
+func TestRefreshOrderStatusAnnouncesPaymentOnce(t *testing.T) {
+	orders, store, gateway := newTestOrderService()
+	payments := NewPaymentService(store, gateway, utils.Company{}, 0)
+	ctx := context.Background()
+
+	order, err := orders.Checkout(ctx, models.CheckoutRequest{PaymentMethodID: 21, Language: "en", Products: []models.CheckoutItem{{ID: 1, Quantity: 1}}})
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	gateway.statuses = map[string]string{"trx-1": "capture"}
+	if _, err := payments.RefreshOrderStatus(ctx, order.ID); err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if err := orders.UpdateOrderStatus(ctx, order.ID, models.OrderStatusReadyForPickup); err != nil {
+		t.Fatalf("mark ready: %v", err)
+	}
+	gateway.statuses["trx-1"] = "settlement"
+	if _, err := payments.RefreshOrderStatus(ctx, order.ID); err != nil {
+		t.Fatalf("settlement: %v", err)
+	}
+
+	var kinds []string
+	for _, email := range store.Outbox() {
+		kinds = append(kinds, email.Kind)
+	}
+	want := []string{helpers.TemplateInvoice, helpers.TemplatePaymentReceived, helpers.TemplateReadyForPickup}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Errorf("got emails %v, want %v", kinds, want)
+	}
+}
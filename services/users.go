@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dimassfeb-09/pestapasta-be/apperror"
+	"github.com/dimassfeb-09/pestapasta-be/repositories"
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserService authenticates staff.
+type UserService struct {
+	store     repositories.Store
+	jwtSecret string
+}
+
+func NewUserService(store repositories.Store, jwtSecret string) *UserService {
+	return &UserService{store: store, jwtSecret: jwtSecret}
+}
+
+// Login checks the credentials and returns a signed JWT. Unknown users and wrong
+// passwords get the same error so usernames cannot be probed.
+func (s *UserService) Login(ctx context.Context, username, password string) (string, error) {
+	user, err := s.store.Users().FindByUsername(ctx, username)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return "", apperror.Unauthorized("Invalid Username or password")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return "", apperror.Unauthorized("Invalid Username or password")
+	}
+
+	return utils.GenerateJWT(s.jwtSecret, uint(user.ID), user.Username)
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// gorm v1 has no context, so it travels in the scope settings instead.
+const (
+	gormContextKey = "tracing:context"
+	gormSpanKey    = "tracing:span"
+)
+
+// WithContext returns db carrying ctx. Queries run through it become children of the
+// span in ctx, queries without a context are not traced.
+func WithContext(db *gorm.DB, ctx context.Context) *gorm.DB {
+	return db.Set(gormContextKey, ctx)
+}
+
+// RegisterGormCallbacks wraps every statement gorm executes in a span. Only the SQL with
+// placeholders is recorded, never the values.
+func RegisterGormCallbacks(db *gorm.DB) {
+	callback := db.Callback()
+	callback.Create().Before("gorm:create").Register("tracing:before_create", startGormSpan("INSERT"))
+	callback.Create().After("gorm:create").Register("tracing:after_create", endGormSpan)
+	callback.Query().Before("gorm:query").Register("tracing:before_query", startGormSpan("SELECT"))
+	callback.Query().After("gorm:query").Register("tracing:after_query", endGormSpan)
+	callback.Update().Before("gorm:update").Register("tracing:before_update", startGormSpan("UPDATE"))
+	callback.Update().After("gorm:update").Register("tracing:after_update", endGormSpan)
+	callback.Delete().Before("gorm:delete").Register("tracing:before_delete", startGormSpan("DELETE"))
+	callback.Delete().After("gorm:delete").Register("tracing:after_delete", endGormSpan)
+	callback.RowQuery().Before("gorm:row_query").Register("tracing:before_row_query", startGormSpan("SELECT"))
+	callback.RowQuery().After("gorm:row_query").Register("tracing:after_row_query", endGormSpan)
+}
+
+func startGormSpan(operation string) func(*gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		value, ok := scope.Get(gormContextKey)
+		if !ok {
+			return
+		}
+		ctx, _ := value.(context.Context)
+		if ctx == nil {
+			return
+		}
+
+		name, table := operation, scope.TableName()
+		if table != "" {
+			name += " " + table
+		}
+		_, span := Tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperationName(operation),
+				semconv.DBCollectionName(table),
+			))
+		scope.Set(gormSpanKey, span)
+	}
+}
+
+func endGormSpan(scope *gorm.Scope) {
+	value, ok := scope.Get(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+
+	span.SetAttributes(
+		semconv.DBQueryText(scope.SQL),
+		attribute.Int64("db.rows_affected", scope.DB().RowsAffected),
+	)
+	// A missing row is an answer, not a failure
+	err := scope.DB().Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	End(span, err)
+}
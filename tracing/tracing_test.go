@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dimassfeb-09/pestapasta-be/utils"
+)
+
+func TestSetup(t *testing.T) {
+	for _, exporter := range []string{"", "none", "stdout", "otlp"} {
+		shutdown, err := Setup(context.Background(), utils.Tracing{Exporter: exporter, Endpoint: "http://localhost:4318"}, "test")
+		if err != nil {
+			t.Fatalf("%q: %v", exporter, err)
+		}
+		// Nothing was recorded, so flushing does not need the collector
+		if err := shutdown(context.Background()); err != nil {
+			t.Errorf("%q: shutdown: %v", exporter, err)
+		}
+	}
+
+	if _, err := Setup(context.Background(), utils.Tracing{Exporter: "jaeger"}, "test"); err == nil {
+		t.Error("expected an unknown exporter to be rejected")
+	}
+}
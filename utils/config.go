@@ -0,0 +1,358 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Database holds the PostgreSQL connection settings.
+type Database struct {
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+	SSLMode  string `json:"ssl_mode"`
+}
+
+// DSN returns the connection string for gorm.Open.
+func (d Database) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s", d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode)
+}
+
+// Midtrans holds the payment gateway credentials.
+type Midtrans struct {
+	ServerKey string `json:"server_key"`
+	BaseURL   string `json:"base_url"` // e.g. https://api.sandbox.midtrans.com/v2
+
+	// StatusRefreshSeconds is how long a pending payment status is served from the
+	// database before Midtrans is asked again
+	StatusRefreshSeconds int `json:"status_refresh_seconds"`
+}
+
+// Email holds the SMTP credentials.
+type Email struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// Company holds the business details printed on invoices.
+type Company struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// Mail configures how outgoing email is delivered.
+type Mail struct {
+	Transport          string `json:"transport"` // "smtp" or "capture"
+	Host               string `json:"host"`
+	Port               int    `json:"port"`
+	From               string `json:"from"`
+	TLS                string `json:"tls"` // "ssl" for implicit TLS, anything else uses STARTTLS when offered
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	CaptureDir         string `json:"capture_dir"` // Optional directory where captured emails are also written
+}
+
+// Tracing selects where OpenTelemetry spans are exported.
+type Tracing struct {
+	Exporter string `json:"exporter"` // "none", "stdout" or "otlp"
+	Endpoint string `json:"endpoint"` // OTLP over HTTP, e.g. http://localhost:4318
+}
+
+// Limit is a token bucket: it holds up to Burst requests and refills PerMinute of them
+// every minute.
+type Limit struct {
+	PerMinute float64 `json:"per_minute"`
+	Burst     int     `json:"burst"`
+}
+
+// RateLimit limits how often one client IP may call each route. Routes are keyed by
+// method and path template without the version prefix, e.g. "POST /checkout", and
+// fall back to Default.
+type RateLimit struct {
+	Enabled bool             `json:"enabled"`
+	Default Limit            `json:"default"`
+	Routes  map[string]Limit `json:"routes"`
+}
+
+// Config is the application configuration. It is loaded once at startup by LoadConfig
+// and handed to the parts of the application that need it.
+type Config struct {
+	AppEnv       string     `json:"app_env"`
+	Port         string     `json:"port"`
+	Database     Database   `json:"database"`
+	Midtrans     Midtrans   `json:"midtrans"`
+	SecretKeyJWT string     `json:"secret_key_jwt"`
+	Email        Email      `json:"email"`
+	Mail         Mail       `json:"mail"`
+	Company      Company    `json:"company"`
+	LogLevel     slog.Level `json:"log_level"` // debug, info (default), warn or error
+	Tracing      Tracing    `json:"tracing"`
+	RateLimit    RateLimit  `json:"rate_limit"`
+
+	// TrustedPlatform names the load balancer whose client IP header is trusted:
+	// "flyio", "cloudflare" or empty to use the connection's address
+	TrustedPlatform string `json:"trusted_platform"`
+}
+
+// IsProduction reports whether the application runs against production services.
+func (c Config) IsProduction() bool {
+	return c.AppEnv == "production"
+}
+
+// LoadConfig builds the configuration from, in increasing priority, built-in defaults,
+// the optional JSON file at path (or CONFIG_FILE when path is empty), and environment
+// variables, including those in a .env file. The result is validated before it is returned.
+func LoadConfig(path string) (Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Config{}, fmt.Errorf("failed to load .env file: %w", err)
+	}
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	var file []byte
+	if path != "" {
+		var err error
+		if file, err = os.ReadFile(path); err != nil {
+			return Config{}, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	// The environment decides the defaults, so find it before anything else
+	var fileEnv struct {
+		AppEnv string `json:"app_env"`
+	}
+	if file != nil {
+		if err := json.Unmarshal(file, &fileEnv); err != nil {
+			return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+	appEnv := getEnv("APP_ENV", fileEnv.AppEnv)
+	if appEnv == "" {
+		appEnv = "local"
+	}
+
+	cfg := defaultConfig(appEnv)
+	if file != nil {
+		if err := json.Unmarshal(file, &cfg); err != nil {
+			return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+	cfg.AppEnv = appEnv
+
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func defaultConfig(appEnv string) Config {
+	cfg := Config{
+		AppEnv: appEnv,
+		Port:   "8081",
+		Database: Database{
+			Host:    "localhost",
+			Port:    "5432",
+			User:    "postgres",
+			Name:    "pestapasta-db",
+			SSLMode: "disable",
+		},
+		Midtrans: Midtrans{BaseURL: "https://api.sandbox.midtrans.com/v2", StatusRefreshSeconds: 30},
+		// Only production talks to a real SMTP server unless configured otherwise
+		Mail: Mail{
+			Transport: "capture",
+			Host:      "smtp.gmail.com",
+			Port:      587,
+			TLS:       "starttls",
+		},
+		Company: Company{
+			Name:  "Pesta Pasta",
+			Email: "support@pestapasta.com",
+			Phone: "123-456-7890",
+		},
+		// Checkout and status checks call Midtrans, login guesses passwords
+		RateLimit: RateLimit{
+			Enabled: true,
+			Default: Limit{PerMinute: 120, Burst: 60},
+			Routes: map[string]Limit{
+				"POST /checkout":         {PerMinute: 6, Burst: 3},
+				"POST /login":            {PerMinute: 5, Burst: 5},
+				"GET /orders/:id/status": {PerMinute: 20, Burst: 5},
+				"GET /orders/:id":        {PerMinute: 30, Burst: 10},
+			},
+		},
+	}
+
+	if appEnv == "production" {
+		cfg.Port = "8080"
+		cfg.Database = Database{Port: "5432", SSLMode: "require"}
+		cfg.Midtrans.BaseURL = "https://api.midtrans.com/v2"
+		cfg.Mail.Transport = "smtp"
+		cfg.TrustedPlatform = "flyio"
+	} else {
+		cfg.Database.Password = "postgres"
+	}
+	return cfg
+}
+
+// applyEnv overrides cfg with the environment variables that are set. Database and
+// Midtrans variables carry a _PRODUCTION or _LOCAL/_SANDBOX suffix for the environment.
+func (c *Config) applyEnv() error {
+	dbSuffix, midtransSuffix := "_LOCAL", "_SANDBOX"
+	if c.IsProduction() {
+		dbSuffix, midtransSuffix = "_PRODUCTION", "_PRODUCTION"
+	}
+
+	setString(&c.Port, "PORT")
+	setString(&c.Database.Host, "DB_HOST"+dbSuffix)
+	setString(&c.Database.Port, "DB_PORT"+dbSuffix)
+	setString(&c.Database.User, "DB_USER"+dbSuffix)
+	setString(&c.Database.Password, "DB_PASSWORD"+dbSuffix)
+	setString(&c.Database.Name, "DB_NAME"+dbSuffix)
+	setString(&c.Database.SSLMode, "SSL_MODE"+dbSuffix)
+	setString(&c.Midtrans.BaseURL, "MIDTRANS_SERVEL_URL"+midtransSuffix)
+	setString(&c.Midtrans.ServerKey, "MIDTRANS_SERVER_KEY"+midtransSuffix)
+	setString(&c.SecretKeyJWT, "SECRET_KEY_JWT")
+	setString(&c.Email.User, "EMAIL_USER_MAILER")
+	setString(&c.Email.Password, "EMAIL_PASSWORD_MAILER")
+	setString(&c.Mail.Transport, "MAIL_TRANSPORT")
+	setString(&c.Mail.Host, "SMTP_HOST")
+	setString(&c.Mail.From, "SMTP_FROM")
+	setString(&c.Mail.TLS, "SMTP_TLS")
+	setString(&c.Mail.CaptureDir, "MAIL_CAPTURE_DIR")
+	setString(&c.Company.Name, "COMPANY_NAME")
+	setString(&c.Company.Email, "COMPANY_EMAIL")
+	setString(&c.Company.Phone, "COMPANY_PHONE")
+	setString(&c.Tracing.Exporter, "TRACING_EXPORTER")
+	setString(&c.Tracing.Endpoint, "OTEL_EXPORTER_OTLP_ENDPOINT")
+	setString(&c.TrustedPlatform, "TRUSTED_PLATFORM")
+
+	if value, ok := os.LookupEnv("SMTP_PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP_PORT %q", value)
+		}
+		c.Mail.Port = port
+	}
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := c.LogLevel.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid LOG_LEVEL %q", value)
+		}
+	}
+	if value, ok := os.LookupEnv("SMTP_INSECURE_SKIP_VERIFY"); ok {
+		skip, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP_INSECURE_SKIP_VERIFY %q", value)
+		}
+		c.Mail.InsecureSkipVerify = skip
+	}
+	if value, ok := os.LookupEnv("MIDTRANS_STATUS_REFRESH_SECONDS"); ok {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid MIDTRANS_STATUS_REFRESH_SECONDS %q", value)
+		}
+		c.Midtrans.StatusRefreshSeconds = seconds
+	}
+	if value, ok := os.LookupEnv("RATE_LIMIT_ENABLED"); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid RATE_LIMIT_ENABLED %q", value)
+		}
+		c.RateLimit.Enabled = enabled
+	}
+
+	if c.Mail.From == "" {
+		c.Mail.From = c.Email.User
+	}
+	return nil
+}
+
+// Validate reports every missing or invalid setting at once. Secrets are only
+// required in production, local development runs with the defaults.
+func (c Config) Validate() error {
+	var problems []string
+
+	if _, err := strconv.Atoi(c.Port); err != nil {
+		problems = append(problems, fmt.Sprintf("PORT %q is not a number", c.Port))
+	}
+	switch c.Mail.Transport {
+	case "smtp", "capture":
+	default:
+		problems = append(problems, fmt.Sprintf("MAIL_TRANSPORT %q must be smtp or capture", c.Mail.Transport))
+	}
+	switch c.Tracing.Exporter {
+	case "", "none", "stdout", "otlp":
+	default:
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER %q must be none, stdout or otlp", c.Tracing.Exporter))
+	}
+	if c.Midtrans.StatusRefreshSeconds < 0 {
+		problems = append(problems, fmt.Sprintf("MIDTRANS_STATUS_REFRESH_SECONDS %d must not be negative", c.Midtrans.StatusRefreshSeconds))
+	}
+	switch c.TrustedPlatform {
+	case "", "flyio", "cloudflare":
+	default:
+		problems = append(problems, fmt.Sprintf("TRUSTED_PLATFORM %q must be flyio, cloudflare or empty", c.TrustedPlatform))
+	}
+	if c.RateLimit.Enabled {
+		limits := map[string]Limit{"default": c.RateLimit.Default}
+		for route, limit := range c.RateLimit.Routes {
+			limits[route] = limit
+		}
+		for name, limit := range limits {
+			if limit.PerMinute <= 0 || limit.Burst < 1 {
+				problems = append(problems, fmt.Sprintf("rate limit %q needs a positive per_minute and burst", name))
+			}
+		}
+	}
+
+	if c.IsProduction() {
+		required := map[string]string{
+			"DB_HOST_PRODUCTION":             c.Database.Host,
+			"DB_USER_PRODUCTION":             c.Database.User,
+			"DB_PASSWORD_PRODUCTION":         c.Database.Password,
+			"DB_NAME_PRODUCTION":             c.Database.Name,
+			"MIDTRANS_SERVER_KEY_PRODUCTION": c.Midtrans.ServerKey,
+			"SECRET_KEY_JWT":                 c.SecretKeyJWT,
+		}
+		if c.Mail.Transport == "smtp" {
+			required["EMAIL_USER_MAILER"] = c.Email.User
+			required["EMAIL_PASSWORD_MAILER"] = c.Email.Password
+		}
+		for key, value := range required {
+			if value == "" {
+				problems = append(problems, key+" is required in production")
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+}
+
+func setString(target *string, key string) {
+	if value, ok := os.LookupEnv(key); ok {
+		*target = value
+	}
+}
+
+// getEnv retrieves the value of an environment variable or returns a default value if not set
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
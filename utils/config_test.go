@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigFailsFastInProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+
+	_, err := LoadConfig("")
+	if err == nil {
+		t.Fatal("expected missing production secrets to be rejected")
+	}
+	for _, key := range []string{"DB_PASSWORD_PRODUCTION", "MIDTRANS_SERVER_KEY_PRODUCTION", "SECRET_KEY_JWT", "EMAIL_PASSWORD_MAILER"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error should mention %s: %v", key, err)
+		}
+	}
+}
+
+func TestLoadConfigFileAndEnvPriority(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	file := `{"port": "9000", "company": {"name": "Pasta Bar"}, "mail": {"port": 2525}}`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APP_ENV", "local")
+	t.Setenv("PORT", "8080")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "8080" {
+		t.Errorf("PORT should override the file, got %q", cfg.Port)
+	}
+	if cfg.Company.Name != "Pasta Bar" || cfg.Mail.Port != 2525 {
+		t.Errorf("file values not applied: %+v", cfg)
+	}
+	if cfg.Company.Email != "support@pestapasta.com" || cfg.Mail.Transport != "capture" {
+		t.Errorf("defaults not kept for values missing from the file: %+v", cfg)
+	}
+}
+
+func TestLoadConfigRejectsInvalidNumbers(t *testing.T) {
+	t.Setenv("APP_ENV", "local")
+	t.Setenv("SMTP_PORT", "smtp")
+
+	if _, err := LoadConfig(""); err == nil {
+		t.Fatal("expected invalid SMTP_PORT to be rejected")
+	}
+}
+
+func TestLoadConfigLogLevel(t *testing.T) {
+	t.Setenv("APP_ENV", "local")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	cfg, err := LoadConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LogLevel != slog.LevelDebug {
+		t.Errorf("got level %v, want debug", cfg.LogLevel)
+	}
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	if _, err := LoadConfig(""); err == nil {
+		t.Fatal("expected invalid LOG_LEVEL to be rejected")
+	}
+}
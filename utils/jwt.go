@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dimassfeb-09/pestapasta-be/i18n"
 	"github.com/golang-jwt/jwt"
 )
 
-func GenerateJWT(userID uint, email string) (string, error) {
-	secretKey := GetENV().SecretKeyJWT
+func GenerateJWT(secretKey string, userID uint, email string) (string, error) {
 	claims := jwt.MapClaims{
 		"userID": userID,
 		"email":  email,
@@ -18,10 +18,7 @@ func GenerateJWT(userID uint, email string) (string, error) {
 	return token.SignedString([]byte(secretKey))
 }
 
-func ValidateJWT(tokenString string) (jwt.MapClaims, error) {
-	// Mendapatkan SecretKey untuk memvalidasi JWT
-	secretKey := GetENV().SecretKeyJWT
-
+func ValidateJWT(secretKey, tokenString string) (jwt.MapClaims, error) {
 	// Mem-parsing token untuk memvalidasi tanda tangan dan mengambil klaim
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Memastikan algoritma tanda tangan adalah HS256
@@ -41,11 +38,11 @@ func ValidateJWT(tokenString string) (jwt.MapClaims, error) {
 		// Validasi tambahan: memeriksa apakah token sudah kedaluwarsa
 		if exp, ok := claims["exp"].(float64); ok {
 			if time.Now().Unix() > int64(exp) {
-				return nil, fmt.Errorf("token expired")
+				return nil, i18n.Errorf("token expired")
 			}
 		}
 		return claims, nil
 	}
 
-	return nil, fmt.Errorf("invalid token")
+	return nil, i18n.Errorf("invalid token")
 }
@@ -1,9 +0,0 @@
-package utils
-
-import "fmt"
-
-var url = GetENV().MidtransUrl
-
-var (
-	EndpointMidtransCharge = fmt.Sprintf("%s/charge", url)
-)